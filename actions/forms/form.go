@@ -120,7 +120,8 @@ func (form *Form) fillProvidedButNotRequested(requestedSlot string, tracker *ras
 		var mappings []SlotMapping
 
 		for _, mapping := range form.mappingsFor(slotName) {
-			mappings = append(mappings, SlotMapping{Intents: mapping.Intents, FromEntity: mapping.FromEntity})
+			mappings = append(mappings, SlotMapping{Intents: mapping.Intents, FromEntity: mapping.FromEntity,
+				Role: mapping.Role, Group: mapping.Group})
 		}
 
 		newEvents = append(newEvents, form.slotEventsFor(slotName, mappings, tracker)...)
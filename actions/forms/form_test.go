@@ -242,3 +242,23 @@ func TestRequestNextSlot(t *testing.T) {
 	expectedResponses := []responses.Message{{Template: fmt.Sprintf("utter_ask_%s", requiredSlot)}}
 	assert.ElementsMatch(t, expectedResponses, dispatcher.Responses())
 }
+
+func TestFillOtherSlotsFromEntitiesWithRoles(t *testing.T) {
+	requested := "departure"
+
+	lastMessage := events.ParseData{Entities: []events.Entity{
+		{Name: "city", Value: "Berlin", Role: "departure"},
+		{Name: "city", Value: "Paris", Role: "destination"},
+	}}
+	tracker := rasa.Tracker{LatestMessage: lastMessage, Slots: map[string]interface{}{requestedSlot: requested}}
+
+	mappings := map[string][]SlotMapping{requested: {{FromEntity: "city", Role: "departure"}},
+		"destination": {{FromEntity: "city", Role: "destination"}}}
+	testForm := Form{FormName: "flight", RequiredSlots: []string{requested, "destination"}, SlotMappings: mappings}
+
+	newEvents := testForm.slotCandidates(&tracker)
+
+	expectedEvents := []events.SlotSet{{Name: "destination", Value: "Paris"}, {Name: requested, Value: "Berlin"}}
+
+	assert.ElementsMatch(t, expectedEvents, newEvents)
+}
@@ -2,6 +2,7 @@ package forms
 
 import (
 	"github.com/wochinge/go-rasa-sdk/rasa"
+	"github.com/wochinge/go-rasa-sdk/rasa/events"
 )
 
 // SlotMapping specifies which information is used to fill a form slot.
@@ -10,8 +11,13 @@ type SlotMapping struct {
 	ApplyToFirstRunOnly bool
 	// FromText fills the requested slot with the user message.
 	FromText bool
-	// FromEntity fills the slot with an entity of a given name.
+	// FromEntity fills the slot with an entity of a given name. If several entities match, the slot is filled with a
+	// list of their values.
 	FromEntity string
+	// Role restricts `FromEntity` to entities with this role. If empty, only entities without a role match.
+	Role string
+	// Group restricts `FromEntity` to entities of this group. If empty, only entities without a group match.
+	Group string
 	// Intents which the latest message has to have that this mapping applies.
 	// If `nil` and `ExcludedIntents` is also `nil`, all intents are valid.
 	Intents []string
@@ -31,8 +37,8 @@ func (mapping SlotMapping) apply(form *Form, tracker *rasa.Tracker) (interface{}
 		return nil, false
 	}
 
-	if entity, found := latestMessage.EntityFor(mapping.FromEntity); found && mapping.FromEntity != "" {
-		return entity, true
+	if value, found := mapping.entityValue(latestMessage); found {
+		return value, true
 	}
 
 	if mapping.FromText {
@@ -46,6 +52,23 @@ func (mapping SlotMapping) apply(form *Form, tracker *rasa.Tracker) (interface{}
 	return nil, false
 }
 
+func (mapping SlotMapping) entityValue(message *events.ParseData) (interface{}, bool) {
+	if mapping.FromEntity == "" {
+		return nil, false
+	}
+
+	values := message.EntitiesFor(mapping.FromEntity, mapping.Role, mapping.Group)
+
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	default:
+		return values, true
+	}
+}
+
 func (mapping SlotMapping) allows(intentName string) bool {
 	if mapping.Intents == nil && mapping.ExcludedIntents == nil {
 		return true
@@ -113,3 +113,37 @@ func TestMappingOnlyFirstRunIfNotFirstRun(t *testing.T) {
 	_, found := SlotMapping{FromText: true, ApplyToFirstRunOnly: true}.apply(&Form{FormName: formName}, &tracker)
 	assert.False(t, found)
 }
+
+func TestFillSlotFromEntityWithRole(t *testing.T) {
+	lastMessage := events.ParseData{Entities: []events.Entity{
+		{Name: "city", Value: "Berlin", Role: "departure"},
+		{Name: "city", Value: "Paris", Role: "destination"},
+	}}
+	tracker := rasa.Tracker{LatestMessage: lastMessage}
+
+	value, found := SlotMapping{FromEntity: "city", Role: "destination"}.apply(nil, &tracker)
+	assert.True(t, found)
+	assert.Equal(t, "Paris", value)
+
+	_, found = SlotMapping{FromEntity: "city", Role: "stopover"}.apply(nil, &tracker)
+	assert.False(t, found)
+
+	_, found = SlotMapping{FromEntity: "city"}.apply(nil, &tracker)
+	assert.False(t, found)
+}
+
+func TestFillSlotFromEntityWithGroup(t *testing.T) {
+	lastMessage := events.ParseData{Entities: []events.Entity{
+		{Name: "topping", Value: "cheese", Group: "1"},
+		{Name: "topping", Value: "ham", Group: "1"},
+		{Name: "topping", Value: "olives", Group: "2"},
+	}}
+	tracker := rasa.Tracker{LatestMessage: lastMessage}
+
+	value, found := SlotMapping{FromEntity: "topping", Group: "1"}.apply(nil, &tracker)
+	assert.True(t, found)
+	assert.Equal(t, []interface{}{"cheese", "ham"}, value)
+
+	_, found = SlotMapping{FromEntity: "topping"}.apply(nil, &tracker)
+	assert.False(t, found)
+}
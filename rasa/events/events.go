@@ -210,6 +210,20 @@ func (data ParseData) EntityFor(name string) (interface{}, bool) {
 	return "", false
 }
 
+// EntitiesFor returns the values of all entities with the given name, role and group. As in Rasa, an empty role or
+// group only matches entities without a role or group.
+func (data ParseData) EntitiesFor(name, role, group string) []interface{} {
+	var values []interface{}
+
+	for _, entity := range data.Entities {
+		if entity.Name == name && entity.Role == role && entity.Group == group {
+			values = append(values, entity.Value)
+		}
+	}
+
+	return values
+}
+
 // IntentParseResult of the NLU prediction.
 type IntentParseResult struct {
 	// Name of the intent.
@@ -232,6 +246,13 @@ type Entity struct {
 	Confidence float64 `json:"confidence"`
 	// Extractor is the name of the extractor which extracted the entity.
 	Extractor string `json:"extractor"`
+	// Role of the entity, e.g. to distinguish departure and destination cities
+	// (https://rasa.com/docs/rasa/nlu-training-data/#entities-roles-and-groups).
+	Role string `json:"role,omitempty"`
+	// Group of the entity to group entities which belong together.
+	Group string `json:"group,omitempty"`
+	// Processors are the components which modified the entity, e.g. `EntitySynonymMapper`.
+	Processors []string `json:"processors,omitempty"`
 }
 
 // Bot represents bot messages to the user within a conversation.
@@ -62,3 +62,32 @@ func TestParsedDataEntityForNotExisting(t *testing.T) {
 	_, found := parsed.EntityFor("not there")
 	assert.False(t, found)
 }
+
+func TestParsedDataEntitiesFor(t *testing.T) {
+	parsed := ParseData{Entities: []Entity{
+		{Name: "city", Value: "Berlin", Role: "departure", Group: "1"},
+		{Name: "city", Value: "Paris", Role: "destination", Group: "1"},
+		{Name: "city", Value: "Rome", Role: "destination", Group: "2"},
+		{Name: "city", Value: "Hamburg"},
+		{Name: "city", Value: "Munich", Role: "destination"},
+		{Name: "date", Value: "today"},
+	}}
+
+	assert.Equal(t, []interface{}{"Hamburg"}, parsed.EntitiesFor("city", "", ""))
+	assert.Equal(t, []interface{}{"Munich"}, parsed.EntitiesFor("city", "destination", ""))
+	assert.Empty(t, parsed.EntitiesFor("city", "", "1"))
+	assert.Equal(t, []interface{}{"Paris"}, parsed.EntitiesFor("city", "destination", "1"))
+	assert.Equal(t, []interface{}{"Rome"}, parsed.EntitiesFor("city", "destination", "2"))
+	assert.Empty(t, parsed.EntitiesFor("city", "stopover", ""))
+}
+
+func TestParseEntityWithRoleAndGroup(t *testing.T) {
+	var entity Entity
+
+	err := json.Unmarshal([]byte(`{"entity": "city", "value": "Berlin", "role": "departure", "group": "1",
+		"processors": ["EntitySynonymMapper"]}`), &entity)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Entity{Name: "city", Value: "Berlin", Role: "departure", Group: "1",
+		Processors: []string{"EntitySynonymMapper"}}, entity)
+}
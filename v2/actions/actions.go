@@ -2,9 +2,12 @@
 package actions
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/internal/tracing"
 	"github.com/wochinge/go-rasa-sdk/v2/logging"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
@@ -27,53 +30,210 @@ type NotFoundError struct{ name string }
 func (e *NotFoundError) Error() string { return fmt.Sprintf("action '%s' was not found.", e.name) }
 
 // ExecutionRejectedError happens when the action rejected its execution.
-type ExecutionRejectedError struct{ name string }
+type ExecutionRejectedError struct {
+	name   string
+	reason string
+}
 
 func (e *ExecutionRejectedError) Error() string {
+	if e.reason != "" {
+		return fmt.Sprintf("action '%s' rejected execution: %s.", e.name, e.reason)
+	}
+
 	return fmt.Sprintf("action '%s' rejected execution.", e.name)
 }
 
+// BadInputError happens when the action cannot work with the data it received, e.g. because a required slot is
+// missing.
+type BadInputError struct {
+	name string
+	err  error
+}
+
+func (e *BadInputError) Error() string {
+	return fmt.Sprintf("action '%s' received invalid input: %v.", e.name, e.err)
+}
+
+func (e *BadInputError) Unwrap() error { return e.err }
+
+// InternalError happens when the action failed due to a problem on the side of the action server, e.g. because a
+// backend service is unavailable. Any error returned by a `FallibleAction` which is not a rejection or bad input
+// is treated as internal error.
+type InternalError struct {
+	name string
+	err  error
+}
+
+func (e *InternalError) Error() string { return fmt.Sprintf("action '%s' failed: %v.", e.name, e.err) }
+
+func (e *InternalError) Unwrap() error { return e.err }
+
+// Reject returns an error which tells Rasa Open Source that the action rejected its execution so that other
+// policies can predict a different action.
+func Reject(reason string) error { return &ExecutionRejectedError{reason: reason} }
+
+// BadInput returns an error which states that the action cannot work with the data it received.
+func BadInput(err error) error { return &BadInputError{err: err} }
+
+// Internal returns an error which states that the action failed due to an internal problem.
+func Internal(err error) error { return &InternalError{err: err} }
+
+// executionError attaches the action name to an error returned by an action and maps it to one of the typed errors.
+func executionError(actionName string, err error) error {
+	var rejected *ExecutionRejectedError
+	if errors.As(err, &rejected) {
+		return &ExecutionRejectedError{name: actionName, reason: rejected.reason}
+	}
+
+	var badInput *BadInputError
+	if errors.As(err, &badInput) {
+		return &BadInputError{name: actionName, err: badInput.err}
+	}
+
+	var internal *InternalError
+	if errors.As(err, &internal) {
+		return &InternalError{name: actionName, err: internal.err}
+	}
+
+	return &InternalError{name: actionName, err: err}
+}
+
 // ExecuteAction executes the custom action which was requested by Rasa Open Source.
+// If several actions have the same name, the first one is executed. Use a `Registry` to detect duplicate names and
+// to avoid indexing the actions for every request.
 func ExecuteAction(actionRequest *request.CustomActionRequest,
 	availableActions []Action) (map[string]interface{}, error) {
-	actionToRun := actionFor(actionRequest.ActionToRun, availableActions)
+	return ExecuteActionContext(context.Background(), actionRequest, availableActions)
+}
+
+// ExecuteActionContext executes the custom action which was requested by Rasa Open Source.
+// The given context is passed to actions which implement `ContextAction`.
+func ExecuteActionContext(ctx context.Context, actionRequest *request.CustomActionRequest,
+	availableActions []Action, options ...ExecutionOption) (map[string]interface{}, error) {
+	return execute(ctx, actionRequest, func(name string) Action { return actionFor(name, availableActions) }, options)
+}
+
+// actionFor returns the first of the given actions with the given name or `nil` if there is none.
+func actionFor(name string, availableActions []Action) Action {
+	for _, action := range availableActions {
+		if action.Name() == name {
+			return action
+		}
+	}
+
+	return nil
+}
+
+func execute(ctx context.Context, actionRequest *request.CustomActionRequest, lookup func(name string) Action,
+	options []ExecutionOption) (map[string]interface{}, error) {
+	config := newExecutionConfig(options)
+
+	started := false
+	defer func() {
+		if !started {
+			config.actionFinished()
+		}
+	}()
+
+	actionToRun := lookupAction(ctx, actionRequest.ActionToRun, lookup)
 
 	if actionToRun == nil {
-		log.WithFields(log.Fields{logging.ActionNameKey: actionRequest.ActionToRun}).Warn("Requested action not found.")
+		config.logger.WithFields(log.Fields{logging.ActionNameKey: actionRequest.ActionToRun}).Warn(
+			"Requested action not found.")
 		return nil, &NotFoundError{name: actionRequest.ActionToRun}
 	}
 
-	log.WithFields(
+	config.logger.WithFields(
 		log.Fields{logging.ActionNameKey: actionToRun,
 			logging.ConversationIDKey: actionRequest.Tracker.ConversationID}).Debug("Received request to run action.")
 
-	dispatcher := responses.NewDispatcher()
-	newEvents := actionToRun.Run(&actionRequest.Tracker, &actionRequest.Domain, dispatcher)
+	invocation := &Invocation{
+		ActionName: actionRequest.ActionToRun,
+		Tracker:    &actionRequest.Tracker,
+		Domain:     &actionRequest.Domain,
+		Dispatcher: responses.NewDispatcher(),
+	}
+	timeout := config.timeoutFor(actionRequest.ActionToRun)
+	if timeout > 0 {
+		// Actions which don't respect the context keep running once the timeout expired. They get their own copies so
+		// that they don't modify the request of the caller after the response was sent.
+		invocation.Tracker, invocation.Domain = detachedTracker(invocation.Tracker), detachedDomain(invocation.Domain)
+	}
+
+	handler := config.handlerFor(actionToRun)
+	started = true
+	newEvents, timedOut, err := runWithTimeout(ctx, timeout,
+		func(ctx context.Context) ([]events.Event, error) {
+			defer config.actionFinished()
+			return handler(ctx, invocation)
+		})
+
+	if timedOut {
+		config.logger.WithFields(log.Fields{logging.ActionNameKey: actionRequest.ActionToRun,
+			logging.ConversationIDKey: actionRequest.Tracker.ConversationID}).Warn("Action execution timed out.")
+		config.actionTimedOut()
+
+		return config.timeoutResponse(rasaVersion(actionRequest)), nil
+	}
+
+	if err != nil {
+		config.logger.WithFields(log.Fields{logging.ActionNameKey: actionToRun, logging.ErrorKey: err}).Debug(
+			"Action execution failed.")
+		return nil, executionError(actionRequest.ActionToRun, err)
+	}
 
 	if events.HasRejection(newEvents) {
-		log.WithFields(log.Fields{logging.ActionNameKey: actionToRun}).Debug("Action rejected execution.")
+		config.logger.WithFields(log.Fields{logging.ActionNameKey: actionToRun}).Debug("Action rejected execution.")
 		return nil, &ExecutionRejectedError{name: actionRequest.ActionToRun}
 	}
 
-	log.WithFields(
+	config.logger.WithFields(
 		log.Fields{logging.ActionNameKey: actionToRun, logging.EventKeys: newEvents}).Debug("Action execution finished.")
 
-	return actionResponse(newEvents, dispatcher), nil
+	return actionResponse(eventsForProtocol(actionRequest, newEvents), invocation.Dispatcher,
+		rasaVersion(actionRequest)), nil
 }
 
-func actionFor(name string, customActions []Action) Action {
-	for _, action := range customActions {
-		if action.Name() == name {
-			return action
-		}
-	}
+func lookupAction(ctx context.Context, name string, lookup func(name string) Action) Action {
+	_, span := tracing.Start(ctx, "lookup action", tracing.ActionNameKey.String(name))
+	defer span.End()
 
-	return nil
+	found := lookup(name)
+	span.SetAttributes(tracing.ActionFoundKey.Bool(found != nil))
+
+	return found
 }
 
-func actionResponse(newEvents []events.Event, dispatcher responses.ResponseDispatcher) map[string]interface{} {
+// runWithTracing runs the action within a span. The span is part of the context which is passed to the action so
+// that actions can create child spans.
+func runWithTracing(ctx context.Context, action Action, invocation *Invocation) ([]events.Event, error) {
+	ctx, span := tracing.Start(ctx, "run action",
+		tracing.ActionNameKey.String(action.Name()),
+		tracing.SenderIDKey.String(invocation.Tracker.ConversationID),
+		tracing.InputChannelKey.String(invocation.Tracker.LatestInputChannel),
+		tracing.EventCountKey.Int(len(invocation.Tracker.Events)))
+	defer span.End()
+
+	newEvents, err := fallible(action).RunWithError(ctx, invocation.Tracker, invocation.Domain,
+		invocation.Dispatcher)
+
+	tracing.RecordError(span, err)
+	span.SetAttributes(tracing.NewEventsKey.Int(len(newEvents)))
+
+	return newEvents, err
+}
+
+// actionResponse returns the response body for Rasa Open Source. Messages refer to responses from the domain in the
+// way the given major version of Rasa Open Source expects.
+func actionResponse(newEvents []events.Event, dispatcher responses.ResponseDispatcher,
+	rasaVersion int) map[string]interface{} {
+	messages := make([]*responses.Message, 0, len(dispatcher.Responses()))
+	for _, message := range dispatcher.Responses() {
+		messages = append(messages, message.ForRasaVersion(rasaVersion))
+	}
+
 	return map[string]interface{}{
 		"events":    events.WithTypeKeys(newEvents...),
-		"responses": dispatcher.Responses(),
+		"responses": messages,
 	}
 }
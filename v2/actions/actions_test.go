@@ -1,8 +1,12 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa"
@@ -49,7 +53,7 @@ func TestActionDispatchingResponses(t *testing.T) {
 }
 
 func TestActionResponseEmpty(t *testing.T) {
-	response := actionResponse([]events.Event{}, responses.NewDispatcher())
+	response := actionResponse([]events.Event{}, responses.NewDispatcher(), 0)
 	actualAsJSON, err := json.Marshal(response)
 
 	assert.Nil(t, err)
@@ -63,7 +67,7 @@ func TestActionResponseWithMultipleResponses(t *testing.T) {
 	dispatcher.Utter(&responses.Message{Text: "hi"})
 	dispatcher.Utter(&responses.Message{Template: "utter_ask"})
 
-	response := actionResponse([]events.Event{}, dispatcher)
+	response := actionResponse([]events.Event{}, dispatcher, 0)
 	actualAsJSON, err := json.Marshal(response)
 
 	assert.Nil(t, err)
@@ -72,10 +76,28 @@ func TestActionResponseWithMultipleResponses(t *testing.T) {
 	assert.Equal(t, expectedResponse, string(actualAsJSON))
 }
 
+func TestActionResponseForRasaVersion(t *testing.T) {
+	dispatcher := responses.NewDispatcher()
+	dispatcher.Utter(&responses.Message{Template: "utter_ask"})
+	dispatcher.Utter(&responses.Message{Response: "utter_greet"})
+
+	response, err := json.Marshal(actionResponse([]events.Event{}, dispatcher, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"events":[],"responses":[{"text":"","response":"utter_ask"},`+
+		`{"text":"","response":"utter_greet"}]}`, string(response))
+
+	response, err = json.Marshal(actionResponse([]events.Event{}, dispatcher, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"events":[],"responses":[{"text":"","template":"utter_ask"},`+
+		`{"text":"","template":"utter_greet"}]}`, string(response))
+
+	assert.Equal(t, []*responses.Message{{Template: "utter_ask"}, {Response: "utter_greet"}}, dispatcher.Responses())
+}
+
 func TestActionResponseWithEvents(t *testing.T) {
 	newEvents := []events.Event{&events.Restarted{}, &events.SlotSet{Name: "my cool slot", Value: "best value"}}
 
-	response := actionResponse(newEvents, responses.NewDispatcher())
+	response := actionResponse(newEvents, responses.NewDispatcher(), 0)
 	actualAsJSON, err := json.Marshal(response)
 
 	assert.Nil(t, err)
@@ -99,3 +121,257 @@ func TestActionRejectingExecution(t *testing.T) {
 
 	assert.IsType(t, &ExecutionRejectedError{}, err)
 }
+
+type contextKey string
+
+type ContextTestAction struct {
+	receivedContext context.Context
+}
+
+func (action *ContextTestAction) RunContext(ctx context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	_ responses.ResponseDispatcher) []events.Event {
+	action.receivedContext = ctx
+
+	return []events.Event{&events.SlotSet{Name: "context-slot", Value: ctx.Value(contextKey("key"))}}
+}
+func (action *ContextTestAction) Name() string { return "test-context" }
+
+func TestExecuteContextAction(t *testing.T) {
+	action := &ContextTestAction{}
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name()}
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+	response, err := ExecuteActionContext(ctx, &actionRequest, []Action{Contextual(action)})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "value", action.receivedContext.Value(contextKey("key")))
+
+	expectedEvents := []events.Event{&events.SlotSet{Base: events.Base{Type: "slot"}, Name: "context-slot",
+		Value: "value"}}
+	assert.Equal(t, expectedEvents, response["events"])
+}
+
+func TestRunContextualActionWithoutContext(t *testing.T) {
+	action := &ContextTestAction{}
+
+	newEvents := Contextual(action).Run(&rasa.Tracker{}, &rasa.Domain{}, responses.NewDispatcher())
+
+	assert.Equal(t, context.Background(), action.receivedContext)
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "context-slot"}}, newEvents)
+}
+
+func TestExecuteActionWithContextIgnoresContextForLegacyActions(t *testing.T) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-action"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := ExecuteActionContext(ctx, &actionRequest, []Action{&SimpleTestAction{}})
+
+	assert.Nil(t, err)
+	assert.Len(t, response["events"], 1)
+}
+
+type FallibleTestAction struct {
+	err error
+}
+
+func (action *FallibleTestAction) RunWithError(_ context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) ([]events.Event, error) {
+	if action.err != nil {
+		return nil, action.err
+	}
+
+	dispatcher.Utter(&responses.Message{Text: "all good"})
+
+	return []events.Event{&events.Restarted{}}, nil
+}
+func (action *FallibleTestAction) Name() string { return "test-fallible" }
+
+func executeFallible(err error) (map[string]interface{}, error) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-fallible"}
+
+	return ExecuteAction(&actionRequest, []Action{Fallible(&FallibleTestAction{err: err})})
+}
+
+func TestFallibleActionSucceeds(t *testing.T) {
+	response, err := executeFallible(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*responses.Message{{Text: "all good"}}, response["responses"])
+}
+
+func TestFallibleActionRejects(t *testing.T) {
+	_, err := executeFallible(Reject("no flights found"))
+
+	assert.IsType(t, &ExecutionRejectedError{}, err)
+	assert.Equal(t, "action 'test-fallible' rejected execution: no flights found.", err.Error())
+}
+
+func TestFallibleActionBadInput(t *testing.T) {
+	cause := errors.New("slot 'city' is missing")
+
+	_, err := executeFallible(BadInput(cause))
+
+	assert.IsType(t, &BadInputError{}, err)
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "action 'test-fallible' received invalid input: slot 'city' is missing.", err.Error())
+}
+
+func TestFallibleActionInternalError(t *testing.T) {
+	cause := errors.New("database unavailable")
+
+	_, err := executeFallible(Internal(cause))
+
+	assert.IsType(t, &InternalError{}, err)
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestFallibleActionUntypedError(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	_, err := executeFallible(fmt.Errorf("calling backend: %w", cause))
+
+	assert.IsType(t, &InternalError{}, err)
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "action 'test-fallible' failed: calling backend: connection refused.", err.Error())
+}
+
+func TestFallibleActionRunWithoutContextRejectsOnError(t *testing.T) {
+	action := Fallible(&FallibleTestAction{err: errors.New("fail")})
+
+	newEvents := action.Run(&rasa.Tracker{}, &rasa.Domain{}, responses.NewDispatcher())
+
+	assert.True(t, events.HasRejection(newEvents))
+}
+
+type SlowAction struct {
+	delay    time.Duration
+	canceled chan struct{}
+}
+
+func (action *SlowAction) RunContext(ctx context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	select {
+	case <-time.After(action.delay):
+		dispatcher.Utter(&responses.Message{Text: "done"})
+	case <-ctx.Done():
+		close(action.canceled)
+	}
+
+	return []events.Event{&events.Restarted{}}
+}
+func (action *SlowAction) Name() string { return "test-slow" }
+
+func TestActionTimeoutWithFallback(t *testing.T) {
+	action := &SlowAction{delay: time.Minute, canceled: make(chan struct{})}
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name()}
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{Contextual(action)},
+		WithTimeout(10*time.Millisecond), WithTimeoutFallback(&responses.Message{Template: "utter_try_later"}))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []events.Event{}, response["events"])
+	assert.Equal(t, []*responses.Message{{Template: "utter_try_later"}}, response["responses"])
+
+	select {
+	case <-action.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("action context was not canceled")
+	}
+}
+
+func TestActionTimeoutWithoutFallback(t *testing.T) {
+	action := &SlowAction{delay: time.Minute, canceled: make(chan struct{})}
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name()}
+	timedOut := false
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{Contextual(action)},
+		WithTimeout(10*time.Millisecond), WithTimeoutCallback(func() { timedOut = true }))
+
+	assert.Nil(t, err)
+	assert.True(t, timedOut)
+
+	actualAsJSON, err := json.Marshal(response)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"events":[],"responses":[]}`, string(actualAsJSON))
+}
+
+func TestPerActionTimeoutTakesPrecedence(t *testing.T) {
+	action := &SlowAction{delay: 20 * time.Millisecond, canceled: make(chan struct{})}
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name()}
+	timedOut := false
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{Contextual(action)},
+		WithTimeout(time.Millisecond), WithActionTimeout(action.Name(), time.Minute),
+		WithTimeoutCallback(func() { timedOut = true }))
+
+	assert.Nil(t, err)
+	assert.False(t, timedOut)
+	assert.Equal(t, []*responses.Message{{Text: "done"}}, response["responses"])
+}
+
+type ContextIgnoringAction struct {
+	release chan struct{}
+}
+
+func (action *ContextIgnoringAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	<-action.release
+
+	tracker.Slots["late"] = true
+	tracker.Events = append(tracker.Events, &events.Restarted{})
+	domain.Slots["late"] = rasa.Slot{Type: "bool"}
+	dispatcher.Utter(&responses.Message{Text: "too late"})
+
+	return []events.Event{}
+}
+func (action *ContextIgnoringAction) Name() string { return "test-ignoring" }
+
+// Run with `-race` to detect actions which modify the request after the timeout expired.
+func TestActionIgnoringContextDoesNotModifyRequestAfterTimeout(t *testing.T) {
+	action := &ContextIgnoringAction{release: make(chan struct{})}
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name(),
+		Tracker: rasa.Tracker{Slots: map[string]interface{}{"cuisine": "greek"}},
+		Domain:  rasa.Domain{Slots: map[string]rasa.Slot{"cuisine": {Type: "text"}}}}
+	finished := make(chan struct{})
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{action},
+		WithTimeout(10*time.Millisecond), WithFinishedCallback(func() { close(finished) }))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*responses.Message{}, response["responses"])
+
+	close(action.release)
+
+	actionRequest.Tracker.Slots["cuisine"] = "italian"
+	actionRequest.Tracker.Events = append(actionRequest.Tracker.Events, &events.Restarted{})
+	actionRequest.Domain.Slots["location"] = rasa.Slot{Type: "text"}
+
+	<-finished
+
+	assert.Equal(t, map[string]interface{}{"cuisine": "italian"}, actionRequest.Tracker.Slots)
+	assert.Len(t, actionRequest.Tracker.Events, 1)
+	assert.NotContains(t, actionRequest.Domain.Slots, "late")
+}
+
+type PanickingAction struct{}
+
+func (action *PanickingAction) Run(_ *rasa.Tracker, _ *rasa.Domain, _ responses.ResponseDispatcher) []events.Event {
+	panic("boom")
+}
+func (action *PanickingAction) Name() string { return "test-panic" }
+
+func TestPanicInActionWithTimeoutIsReraised(t *testing.T) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-panic"}
+
+	defer func() {
+		recovered := recover()
+
+		assert.IsType(t, &PanicError{}, recovered)
+		assert.Equal(t, "boom", recovered.(*PanicError).Error())
+		assert.Contains(t, string(recovered.(*PanicError).Stack), "PanickingAction")
+	}()
+
+	_, _ = ExecuteActionContext(context.Background(), &actionRequest, []Action{&PanickingAction{}},
+		WithTimeout(time.Second))
+}
@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// ContextAction is the interface for custom actions which want to honour the deadline and cancellation of the
+// request from Rasa Open Source, e.g. when calling databases or other HTTP APIs.
+// Use `Contextual` to serve a `ContextAction` together with your other actions.
+type ContextAction interface {
+	// RunContext runs the custom action in the given context and returns new conversation events.
+	// The context is canceled when Rasa Open Source closes the connection.
+	// Any messages dispatched will be sent to the user.
+	RunContext(ctx context.Context, tracker *rasa.Tracker, domain *rasa.Domain,
+		dispatcher responses.ResponseDispatcher) []events.Event
+	// Name returns the name of the custom action.
+	Name() string
+}
+
+// Contextual turns a `ContextAction` into an `Action` so that it can be passed to the action server.
+// When executed by the action server, the `ContextAction` receives the context of the request from Rasa Open Source.
+func Contextual(action ContextAction) Action {
+	return &contextualAction{action}
+}
+
+type contextualAction struct {
+	ContextAction
+}
+
+func (action *contextualAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	return action.RunContext(context.Background(), tracker, domain, dispatcher)
+}
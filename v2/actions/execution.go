@@ -0,0 +1,209 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// ExecutionOption configures how actions are executed by `ExecuteActionContext`.
+type ExecutionOption func(*executionConfig)
+
+type executionConfig struct {
+	defaultTimeout   time.Duration
+	actionTimeouts   map[string]time.Duration
+	timeoutFallback  *responses.Message
+	middleware       []Middleware
+	actionMiddleware map[string][]Middleware
+	finishedCallback func()
+	timeoutCallback  func()
+	logger           log.FieldLogger
+}
+
+func newExecutionConfig(options []ExecutionOption) *executionConfig {
+	config := &executionConfig{
+		actionTimeouts:   map[string]time.Duration{},
+		actionMiddleware: map[string][]Middleware{},
+		logger:           log.StandardLogger(),
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	return config
+}
+
+// WithTimeout sets the maximum duration of an action execution for all actions. The context which is passed to
+// `ContextAction`s and `FallibleAction`s is canceled when the timeout expires. A response containing only the
+// fallback message (see `WithTimeoutFallback`) is sent to Rasa Open Source so that the conversation can continue.
+// A timeout of `0` disables the timeout.
+// Actions must stop once the context is canceled. Actions which ignore the context keep running in the background
+// with their own copies of the tracker, the domain and the dispatcher, and their results are discarded.
+func WithTimeout(timeout time.Duration) ExecutionOption {
+	return func(config *executionConfig) { config.defaultTimeout = timeout }
+}
+
+// WithActionTimeout sets the timeout for a specific action. It takes precedence over the timeout set by
+// `WithTimeout`.
+func WithActionTimeout(actionName string, timeout time.Duration) ExecutionOption {
+	return func(config *executionConfig) { config.actionTimeouts[actionName] = timeout }
+}
+
+// WithTimeoutFallback sets a message which is sent to the user in case an action timed out. Use the `Template` or
+// `Response` field of the message to send a response from the domain.
+func WithTimeoutFallback(message *responses.Message) ExecutionOption {
+	return func(config *executionConfig) { config.timeoutFallback = message }
+}
+
+// WithFinishedCallback sets a function which is called once the action stopped running. Actions which timed out
+// keep running in the background in case they don't respect the context. The callback is then called after
+// `ExecuteActionContext` returned, e.g. to keep a concurrency slot occupied until the action really finished.
+func WithFinishedCallback(callback func()) ExecutionOption {
+	return func(config *executionConfig) { config.finishedCallback = callback }
+}
+
+// WithTimeoutCallback sets a function which is called in case the action timed out, e.g. to count timeouts.
+func WithTimeoutCallback(callback func()) ExecutionOption {
+	return func(config *executionConfig) { config.timeoutCallback = callback }
+}
+
+// WithLogger sets the logger which is used to log the action execution. By default the standard logrus logger is
+// used.
+func WithLogger(logger log.FieldLogger) ExecutionOption {
+	return func(config *executionConfig) { config.logger = logger }
+}
+
+func (config *executionConfig) actionFinished() {
+	if config.finishedCallback != nil {
+		config.finishedCallback()
+	}
+}
+
+func (config *executionConfig) actionTimedOut() {
+	if config.timeoutCallback != nil {
+		config.timeoutCallback()
+	}
+}
+
+func (config *executionConfig) timeoutFor(actionName string) time.Duration {
+	if timeout, ok := config.actionTimeouts[actionName]; ok {
+		return timeout
+	}
+
+	return config.defaultTimeout
+}
+
+// timeoutResponse returns the events and dispatched messages which are sent to Rasa Open Source in case the action
+// timed out.
+func (config *executionConfig) timeoutResponse(rasaVersion int) map[string]interface{} {
+	dispatcher := responses.NewDispatcher()
+	if config.timeoutFallback != nil {
+		dispatcher.Utter(config.timeoutFallback)
+	}
+
+	return actionResponse([]events.Event{}, dispatcher, rasaVersion)
+}
+
+// detachedTracker copies the tracker so that an action which keeps running after a timeout doesn't modify the
+// tracker of the request. Nested values are shared.
+func detachedTracker(tracker *rasa.Tracker) *rasa.Tracker {
+	detached := *tracker
+	detached.Slots = make(map[string]interface{}, len(tracker.Slots))
+
+	for name, value := range tracker.Slots {
+		detached.Slots[name] = value
+	}
+
+	detached.Events = append([]events.Event(nil), tracker.Events...)
+	detached.RawEvents = append([]json.RawMessage(nil), tracker.RawEvents...)
+
+	return &detached
+}
+
+// detachedDomain copies the domain so that an action which keeps running after a timeout doesn't modify the domain
+// of the request. Nested values are shared.
+func detachedDomain(domain *rasa.Domain) *rasa.Domain {
+	detached := *domain
+	detached.Slots = make(map[string]rasa.Slot, len(domain.Slots))
+
+	for name, slot := range domain.Slots {
+		detached.Slots[name] = slot
+	}
+
+	detached.Forms = make(map[string]map[string]interface{}, len(domain.Forms))
+
+	for name, form := range domain.Forms {
+		detached.Forms[name] = form
+	}
+
+	return &detached
+}
+
+// PanicError wraps a panic which happened in an action which was run with a timeout. The panic is re-raised in the
+// goroutine which executes the action so that it can be handled by the caller.
+type PanicError struct {
+	// Value is the value which was passed to `panic`.
+	Value interface{}
+	// Stack is the stack trace of the goroutine which panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprint(e.Value) }
+
+type executionResult struct {
+	newEvents []events.Event
+	err       error
+	panicked  *PanicError
+}
+
+// runWithTimeout runs the given function and cancels its context once the timeout expires. It returns
+// `timedOut=true` if the function didn't finish in time. Actions which don't respect the context keep running in the
+// background but their results are discarded.
+func runWithTimeout(ctx context.Context, timeout time.Duration,
+	run func(context.Context) ([]events.Event, error)) (newEvents []events.Event, timedOut bool, err error) {
+	if timeout <= 0 {
+		newEvents, err = run(ctx)
+		return newEvents, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	finished := make(chan executionResult, 1)
+
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				finished <- executionResult{panicked: &PanicError{Value: recovered, Stack: debug.Stack()}}
+			}
+		}()
+
+		newEvents, err := run(ctx)
+		finished <- executionResult{newEvents: newEvents, err: err}
+	}()
+
+	select {
+	case result := <-finished:
+		if result.panicked != nil {
+			panic(result.panicked)
+		}
+
+		// Context-aware actions might return before the select notices that the timeout expired.
+		if result.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, true, ctx.Err()
+		}
+
+		return result.newEvents, false, result.err
+	case <-ctx.Done():
+		return nil, errors.Is(ctx.Err(), context.DeadlineExceeded), ctx.Err()
+	}
+}
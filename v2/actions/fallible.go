@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// FallibleAction is the interface for custom actions which can fail, e.g. because a backend is unavailable.
+// Returned errors are reported to Rasa Open Source. Use `Reject` to reject the execution, `BadInput` if the action
+// cannot work with the received data. Any other error is reported as `InternalError`.
+// Use `Fallible` to serve a `FallibleAction` together with your other actions.
+type FallibleAction interface {
+	// RunWithError runs the custom action in the given context and returns new conversation events or an error.
+	// Any messages dispatched will be sent to the user in case no error is returned.
+	RunWithError(ctx context.Context, tracker *rasa.Tracker, domain *rasa.Domain,
+		dispatcher responses.ResponseDispatcher) ([]events.Event, error)
+	// Name returns the name of the custom action.
+	Name() string
+}
+
+// Fallible turns a `FallibleAction` into an `Action` so that it can be passed to the action server.
+func Fallible(action FallibleAction) Action {
+	return &fallibleAction{action}
+}
+
+type fallibleAction struct {
+	FallibleAction
+}
+
+// Run runs the action without context. In case of an error the execution is rejected.
+func (action *fallibleAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	newEvents, err := action.RunWithError(context.Background(), tracker, domain, dispatcher)
+	if err != nil {
+		return []events.Event{&events.ActionExecutionRejected{Action: events.Action{Name: action.Name()}}}
+	}
+
+	return newEvents
+}
+
+// fallible adapts any action so that it can be run like a `FallibleAction`.
+func fallible(action Action) FallibleAction {
+	switch adapted := action.(type) {
+	case FallibleAction:
+		return adapted
+	case ContextAction:
+		return &contextActionAdapter{adapted}
+	default:
+		return &actionAdapter{action}
+	}
+}
+
+type contextActionAdapter struct {
+	ContextAction
+}
+
+func (action *contextActionAdapter) RunWithError(ctx context.Context, tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) ([]events.Event, error) {
+	return action.RunContext(ctx, tracker, domain, dispatcher), nil
+}
+
+type actionAdapter struct {
+	Action
+}
+
+func (action *actionAdapter) RunWithError(_ context.Context, tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) ([]events.Event, error) {
+	return action.Run(tracker, domain, dispatcher), nil
+}
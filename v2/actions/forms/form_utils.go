@@ -3,24 +3,30 @@ package forms
 
 import (
 	"fmt"
+	"sort"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
 	"github.com/wochinge/go-rasa-sdk/v2/logging"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
 )
 
-const requestedSlot = "requested_slot"
-
 type FormValidationAction struct {
 	// FormName is the name of the form.
 	FormName string
 	// Validators specify functions to validate slot candidates.
 	Validators map[string]SlotValidator
 	// Extractors specify functions to extract slot candidates.
-	Extractors        map[string]SlotExtractor
+	Extractors map[string]SlotExtractor
+	// NextSlotRequester sets the slot which is requested next. It takes precedence over `RequiredSlots` when
+	// `requested_slot` is set, i.e. `RequiredSlots` then only resets the slots which are no longer required.
 	NextSlotRequester NextSlotRequester
+	// FormValidator validates all slot candidates together after they were validated by the `Validators`.
+	FormValidator FormValidator
+	// RequiredSlots changes the slots which the form requires depending on the conversation.
+	RequiredSlots RequiredSlotsProvider
 }
 
 // SlotValidator can be used to validate candidates before filling a slot with them.
@@ -38,6 +44,17 @@ type SlotExtractor interface {
 		dispatcher responses.ResponseDispatcher) (extractedValue interface{}, valueFound bool)
 }
 
+// FormValidator can be used to validate the slot candidates of a form together, e.g. to enforce constraints which
+// span several slots.
+type FormValidator interface {
+	// Validate receives the slot candidates after they were validated by the `Validators` of the form. Rejected
+	// candidates have the value `nil`. Slots which were filled earlier are available through the tracker.
+	// Returns the slot values which should be set. Candidates which are not part of the returned values are rejected
+	// and reset to `nil`.
+	Validate(candidates map[string]interface{}, domain *rasa.Domain, tracker *rasa.Tracker,
+		dispatcher responses.ResponseDispatcher) (validatedValues map[string]interface{})
+}
+
 // NextSlotRequester can be used to dynamically set which slot should be request next or if the form should be stopped.
 type NextSlotRequester interface {
 	// NextSlot returns the next slot which should be requested or `shouldRequestNextSlot=false` in case the form
@@ -46,6 +63,19 @@ type NextSlotRequester interface {
 		dispatcher responses.ResponseDispatcher) (nextSlot string, shouldRequestNextSlot bool)
 }
 
+// RequiredSlotsProvider can be used to dynamically add or remove slots from the slots which the form requires,
+// e.g. to only ask for a company name if the user books as business customer.
+// Slots which are no longer required are reset in case they were filled while the form was active. Slots which were
+// filled before the form was activated keep their values. Unless a `NextSlotRequester` is used, the first required
+// slot without value is requested next and the form is completed once all required slots are filled.
+type RequiredSlotsProvider interface {
+	// RequiredSlots receives the required slots of the form as specified in the domain and returns the slots which
+	// the form currently requires in the order in which they should be requested. The tracker contains the
+	// validated slot values.
+	RequiredSlots(domainSlots []string, domain *rasa.Domain, tracker *rasa.Tracker,
+		dispatcher responses.ResponseDispatcher) []string
+}
+
 // Run is executed whenever Rasa Open Source sends a request to validate this form.
 func (action *FormValidationAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
 	dispatcher responses.ResponseDispatcher) []events.Event {
@@ -54,39 +84,218 @@ func (action *FormValidationAction) Run(tracker *rasa.Tracker, domain *rasa.Doma
 		log.Fields{logging.FormNameKey: action.FormName, logging.FormValidationKey: tracker.ActiveLoop.Validate}).Debug(
 		"Validating form.")
 
-	newEvents := make([]events.Event, 0)
+	requiredSlots := domain.RequiredSlots(action.FormName)
+	candidates := extractAndValidate(requiredSlots, action.Extractors, action.Validators, tracker, domain, dispatcher)
+
+	if action.FormValidator != nil && len(candidates) > 0 {
+		candidates = action.validateTogether(candidates, tracker, domain, dispatcher)
+	}
+
+	if action.RequiredSlots != nil {
+		requiredSlots, candidates = action.updateRequiredSlots(requiredSlots, candidates, tracker, domain, dispatcher)
+	}
+
+	newEvents := slotEvents(requiredSlots, candidates)
+
+	switch {
+	case action.NextSlotRequester != nil:
+		if nextSlot, shouldRequestNextSlot := action.NextSlotRequester.NextSlot(
+			domain, tracker, dispatcher); shouldRequestNextSlot {
+			newEvents = append(newEvents, &events.SlotSet{Name: rasa.RequestedSlot, Value: nextSlot})
+		} else {
+			newEvents = append(newEvents, &events.SlotSet{Name: rasa.RequestedSlot, Value: nil})
+		}
+	case action.RequiredSlots != nil:
+		if nextSlot, found := nextRequiredSlot(requiredSlots, tracker); found {
+			newEvents = append(newEvents, &events.SlotSet{Name: rasa.RequestedSlot, Value: nextSlot})
+		} else {
+			newEvents = append(newEvents, &events.SlotSet{Name: rasa.RequestedSlot, Value: nil})
+		}
+	}
+
+	return newEvents
+}
+
+// updateRequiredSlots applies the validated slot values to the tracker and asks the `RequiredSlots` hook which
+// slots the form currently requires. Slots of the domain which are no longer required are reset in case they were
+// filled while the form was active. Returns the required slots and the slot values including the reset slots.
+func (action *FormValidationAction) updateRequiredSlots(domainSlots []string, values map[string]interface{},
+	tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) (requiredSlots []string, updatedValues map[string]interface{}) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	filledByForm := slotsFilledSinceActivation(action.FormName, tracker)
+	for slotName := range values {
+		filledByForm[slotName] = true
+	}
+
+	tracker.Apply(slotEvents(domainSlots, values)...)
+
+	requiredSlots = action.RequiredSlots.RequiredSlots(domainSlots, domain, tracker, dispatcher)
+	required := make(map[string]bool, len(requiredSlots))
+
+	for _, slotName := range requiredSlots {
+		required[slotName] = true
+	}
+
+	for _, slotName := range domainSlots {
+		if !required[slotName] && filledByForm[slotName] && tracker.Slots[slotName] != nil {
+			values[slotName] = nil
+			tracker.Apply(&events.SlotSet{Name: slotName, Value: nil})
+		}
+	}
+
+	return requiredSlots, values
+}
+
+// slotsFilledSinceActivation returns the slots which were set since the form was activated the last time. No slots
+// are returned in case the activation isn't part of the conversation history.
+func slotsFilledSinceActivation(formName string, tracker *rasa.Tracker) map[string]bool {
+	filled := map[string]bool{}
+
+	for i := len(tracker.Events) - 1; i >= 0; i-- {
+		switch event := tracker.Events[i].(type) {
+		case *events.SlotSet:
+			filled[event.Name] = true
+		case *events.ActiveLoop:
+			if event.Name == formName {
+				return filled
+			}
+		case *events.Form:
+			if event.Name == formName {
+				return filled
+			}
+		}
+	}
+
+	return map[string]bool{}
+}
+
+// nextRequiredSlot returns the first required slot which is not filled yet.
+func nextRequiredSlot(requiredSlots []string, tracker *rasa.Tracker) (nextSlot string, found bool) {
+	for _, slotName := range requiredSlots {
+		if tracker.Slots[slotName] == nil {
+			return slotName, true
+		}
+	}
+
+	return "", false
+}
 
-	for slotName, extractor := range action.Extractors {
-		if extractedValue, valueFound := extractor.Extract(domain, tracker, dispatcher); valueFound {
-			tracker.Slots[slotName] = extractedValue
-			tracker.Events = append(tracker.Events, &events.SlotSet{Name: slotName, Value: extractedValue})
+// extractAndValidate extracts custom slots and validates the slot candidates. It returns the validated slot values.
+// Invalid candidates have the value `nil`. Slots are processed in the order of the required slots of the form.
+func extractAndValidate(requiredSlots []string, extractors map[string]SlotExtractor,
+	validators map[string]SlotValidator, tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) map[string]interface{} {
+	for _, slotName := range inSlotOrder(requiredSlots, extractors) {
+		if extractedValue, valueFound := extractors[slotName].Extract(domain, tracker, dispatcher); valueFound {
+			tracker.Apply(&events.SlotSet{Name: slotName, Value: extractedValue})
 		}
 	}
 
 	slotsToValidate := tracker.SlotsToValidate()
-	for slotName, slotValue := range slotsToValidate {
-		if validator, ok := action.Validators[slotName]; ok {
+	validatedValues := make(map[string]interface{}, len(slotsToValidate))
+
+	for _, slotName := range inSlotOrder(requiredSlots, slotsToValidate) {
+		slotValue := slotsToValidate[slotName]
+
+		if validator, ok := validators[slotName]; ok {
 			if validatedValue, isValid := validator.IsValid(slotValue, domain, tracker, dispatcher); isValid {
-				newEvents = append(newEvents, &events.SlotSet{Name: slotName, Value: validatedValue})
+				validatedValues[slotName] = validatedValue
 			} else {
-				newEvents = append(newEvents, &events.SlotSet{Name: slotName, Value: nil})
+				validatedValues[slotName] = nil
 			}
 		} else {
 			// no validator function provided
-			newEvents = append(newEvents, &events.SlotSet{Name: slotName, Value: slotValue})
+			validatedValues[slotName] = slotValue
 		}
 	}
 
-	if action.NextSlotRequester != nil {
-		if nextSlot, shouldRequestNextSlot := action.NextSlotRequester.NextSlot(
-			domain, tracker, dispatcher); shouldRequestNextSlot {
-			newEvents = append(newEvents, &events.SlotSet{Name: requestedSlot, Value: nextSlot})
-		} else {
-			newEvents = append(newEvents, &events.SlotSet{Name: requestedSlot, Value: nil})
+	return validatedValues
+}
+
+// slotEvents returns events which set the slots to the given values in the order of the required slots of the form.
+// validateTogether runs the `FormValidator`. Candidates which the validator left out are reset since Rasa Open
+// Source would otherwise keep the unvalidated value.
+func (action *FormValidationAction) validateTogether(candidates map[string]interface{}, tracker *rasa.Tracker,
+	domain *rasa.Domain, dispatcher responses.ResponseDispatcher) map[string]interface{} {
+	validated := action.FormValidator.Validate(candidates, domain, tracker, dispatcher)
+	if validated == nil {
+		validated = make(map[string]interface{}, len(candidates))
+	}
+
+	for slotName := range candidates {
+		if _, ok := validated[slotName]; !ok {
+			validated[slotName] = nil
 		}
 	}
 
+	return validated
+}
+
+func slotEvents(requiredSlots []string, values map[string]interface{}) []events.Event {
+	newEvents := make([]events.Event, 0, len(values))
+
+	for _, slotName := range inSlotOrder(requiredSlots, values) {
+		newEvents = append(newEvents, &events.SlotSet{Name: slotName, Value: values[slotName]})
+	}
+
 	return newEvents
 }
 
+// inSlotOrder returns the names of the given slots in the order of the required slots of the form. Slots which are
+// not required follow in lexical order.
+func inSlotOrder[V any](requiredSlots []string, slots map[string]V) []string {
+	ordered := make([]string, 0, len(slots))
+	required := make(map[string]bool, len(requiredSlots))
+
+	for _, slotName := range requiredSlots {
+		required[slotName] = true
+
+		if _, ok := slots[slotName]; ok {
+			ordered = append(ordered, slotName)
+		}
+	}
+
+	var others []string
+
+	for slotName := range slots {
+		if !required[slotName] {
+			others = append(others, slotName)
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(ordered, others...)
+}
+
 func (action *FormValidationAction) Name() string { return fmt.Sprintf("validate_%v", action.FormName) }
+
+// Metadata returns the name of the form and the slots which are validated or extracted by the action.
+func (action *FormValidationAction) Metadata() actions.Metadata {
+	return actions.Metadata{FormName: action.FormName, RequiredSlots: slotNames(action.Validators, action.Extractors)}
+}
+
+// slotNames returns the sorted names of the slots which are validated or extracted.
+func slotNames(validators map[string]SlotValidator, extractors map[string]SlotExtractor) []string {
+	slots := map[string]bool{}
+	for slotName := range validators {
+		slots[slotName] = true
+	}
+
+	for slotName := range extractors {
+		slots[slotName] = true
+	}
+
+	names := make([]string, 0, len(slots))
+	for slotName := range slots {
+		names = append(names, slotName)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
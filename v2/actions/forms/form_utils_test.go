@@ -23,11 +23,11 @@ func (v *ExactMatchValidator) IsValid(value interface{}, _ *rasa.Domain, _ *rasa
 func TestFormRun(t *testing.T) {
 	validators, extractors := make(map[string]SlotValidator), make(map[string]SlotExtractor)
 	formValidator := FormValidationAction{
-		"test_form", validators, extractors, nil,
+		FormName: "test_form", Validators: validators, Extractors: extractors,
 	}
 
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil}}
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil}}
 
 	newEvents := formValidator.Run(&tracker, &rasa.Domain{}, responses.NewDispatcher())
 
@@ -44,10 +44,10 @@ func TestFormValidSlot(t *testing.T) {
 	extractors := make(map[string]SlotExtractor)
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, nil,
+		FormName: formName, Validators: validators, Extractors: extractors,
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -72,10 +72,10 @@ func TestFormInValidSlot(t *testing.T) {
 	extractors := make(map[string]SlotExtractor)
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, nil,
+		FormName: formName, Validators: validators, Extractors: extractors,
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -115,10 +115,10 @@ func TestExtractCustomSlot(t *testing.T) {
 	}}
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, nil,
+		FormName: formName, Validators: validators, Extractors: extractors,
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -141,10 +141,10 @@ func TestExtractCustomSlotIfNotFound(t *testing.T) {
 	}}
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, nil,
+		FormName: formName, Validators: validators, Extractors: extractors,
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -165,10 +165,10 @@ func TestExtractCustomSlotAndValidate(t *testing.T) {
 	}}
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, nil,
+		FormName: formName, Validators: validators, Extractors: extractors,
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -202,10 +202,11 @@ func TestRequestNextSlot(t *testing.T) {
 	validators, extractors := make(map[string]SlotValidator), make(map[string]SlotExtractor)
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, &ConstantSlotRequester{"color"},
+		FormName: formName, Validators: validators, Extractors: extractors,
+		NextSlotRequester: &ConstantSlotRequester{"color"},
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -215,7 +216,7 @@ func TestRequestNextSlot(t *testing.T) {
 	newEvents := formValidator.Run(&tracker, &rasa.Domain{}, responses.NewDispatcher())
 
 	expected := []events.Event{
-		&events.SlotSet{Name: requestedSlot, Value: "color"},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: "color"},
 	}
 	assert.ElementsMatch(t, expected, newEvents)
 }
@@ -226,10 +227,11 @@ func TestRequestNoNextSlot(t *testing.T) {
 	validators, extractors := make(map[string]SlotValidator), make(map[string]SlotExtractor)
 	formName := "test_form"
 	formValidator := FormValidationAction{
-		formName, validators, extractors, &ConstantSlotRequester{nil},
+		FormName: formName, Validators: validators, Extractors: extractors,
+		NextSlotRequester: &ConstantSlotRequester{nil},
 	}
 	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
-		Slots: map[string]interface{}{requestedSlot: nil, slotName: "green"},
+		Slots: map[string]interface{}{rasa.RequestedSlot: nil, slotName: "green"},
 		Events: []events.Event{
 			&events.SlotSet{Name: "bla", Value: 5},
 			&events.Action{Name: formName},
@@ -239,7 +241,235 @@ func TestRequestNoNextSlot(t *testing.T) {
 	newEvents := formValidator.Run(&tracker, &rasa.Domain{}, responses.NewDispatcher())
 
 	expected := []events.Event{
-		&events.SlotSet{Name: requestedSlot, Value: nil},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: nil},
 	}
 	assert.ElementsMatch(t, expected, newEvents)
 }
+
+func TestFormMetadata(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName,
+		Validators: map[string]SlotValidator{"color": &ExactMatchValidator{"blue"}, "size": nil},
+		Extractors: map[string]SlotExtractor{"color": nil, "amount": nil}}
+
+	metadata := formValidator.Metadata()
+
+	assert.Equal(t, testFormName, metadata.FormName)
+	assert.Equal(t, []string{"amount", "color", "size"}, metadata.RequiredSlots)
+}
+
+type UtteringValidator struct{}
+
+func (v *UtteringValidator) IsValid(value interface{}, _ *rasa.Domain, _ *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) (interface{}, bool) {
+	dispatcher.Utter(&responses.Message{Text: value.(string)})
+	return value, true
+}
+
+func TestFormValidatesSlotsInOrderOfRequiredSlots(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, Validators: map[string]SlotValidator{
+		"size": &UtteringValidator{}, "color": &UtteringValidator{}, "amount": &UtteringValidator{},
+		"notes": &UtteringValidator{}, "comment": &UtteringValidator{},
+	}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"size", "color", "amount"}}}}
+
+	for i := 0; i < 10; i++ {
+		tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
+			Events: []events.Event{
+				&events.Action{Name: testFormName},
+				&events.SlotSet{Name: "notes", Value: "n"},
+				&events.SlotSet{Name: "amount", Value: "a"},
+				&events.SlotSet{Name: "color", Value: "c"},
+				&events.SlotSet{Name: "comment", Value: "x"},
+				&events.SlotSet{Name: "size", Value: "s"},
+			}}
+		dispatcher := responses.NewDispatcher()
+
+		newEvents := formValidator.Run(&tracker, &domain, dispatcher)
+
+		assert.Equal(t, []events.Event{
+			&events.SlotSet{Name: "size", Value: "s"},
+			&events.SlotSet{Name: "color", Value: "c"},
+			&events.SlotSet{Name: "amount", Value: "a"},
+			&events.SlotSet{Name: "comment", Value: "x"},
+			&events.SlotSet{Name: "notes", Value: "n"},
+		}, newEvents)
+		assert.Equal(t, []*responses.Message{{Text: "s"}, {Text: "c"}, {Text: "a"}, {Text: "x"}, {Text: "n"}},
+			dispatcher.Responses())
+	}
+}
+
+type StayValidator struct{}
+
+func (v *StayValidator) Validate(candidates map[string]interface{}, _ *rasa.Domain, tracker *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) map[string]interface{} {
+	checkIn, ok := candidates["check_in"]
+	if !ok {
+		checkIn = tracker.Slots["check_in"]
+	}
+
+	checkOut, _ := candidates["check_out"].(string)
+	if checkIn != nil && checkOut != "" && checkOut <= checkIn.(string) {
+		dispatcher.Utter(&responses.Message{Template: "utter_check_out_before_check_in"})
+		candidates["check_out"] = nil
+	}
+
+	return candidates
+}
+
+func TestFormValidator(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, FormValidator: &StayValidator{}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"check_in", "check_out"}}}}
+	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
+		Slots: map[string]interface{}{"check_in": "2021-03-28"},
+		Events: []events.Event{
+			&events.Action{Name: testFormName},
+			&events.SlotSet{Name: "check_out", Value: "2021-03-27"},
+		}}
+	dispatcher := responses.NewDispatcher()
+
+	newEvents := formValidator.Run(&tracker, &domain, dispatcher)
+
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "check_out", Value: nil}}, newEvents)
+	assert.Equal(t, []*responses.Message{{Template: "utter_check_out_before_check_in"}}, dispatcher.Responses())
+
+	tracker.Events = append(tracker.Events, &events.Action{Name: testFormName},
+		&events.SlotSet{Name: "check_out", Value: "2021-03-30"})
+
+	newEvents = formValidator.Run(&tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "check_out", Value: "2021-03-30"}}, newEvents)
+}
+
+func TestFormValidatorIsNotCalledWithoutCandidates(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, FormValidator: &StayValidator{}}
+	tracker := rasa.Tracker{Events: []events.Event{&events.Action{Name: testFormName}}}
+
+	newEvents := formValidator.Run(&tracker, &rasa.Domain{}, responses.NewDispatcher())
+
+	assert.Empty(t, newEvents)
+}
+
+type RejectingFormValidator struct{}
+
+func (v *RejectingFormValidator) Validate(map[string]interface{}, *rasa.Domain, *rasa.Tracker,
+	responses.ResponseDispatcher) map[string]interface{} {
+	return nil
+}
+
+func TestFormValidatorResetsOmittedCandidates(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, FormValidator: &RejectingFormValidator{}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"check_in", "check_out"}}}}
+	tracker := rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
+		Events: []events.Event{
+			&events.Action{Name: testFormName},
+			&events.SlotSet{Name: "check_in", Value: "2021-03-28"},
+		}}
+
+	newEvents := formValidator.Run(&tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "check_in", Value: nil}}, newEvents)
+}
+
+type BusinessSlots struct{}
+
+func (p *BusinessSlots) RequiredSlots(domainSlots []string, _ *rasa.Domain, tracker *rasa.Tracker,
+	_ responses.ResponseDispatcher) []string {
+	if tracker.Slots["is_business"] == true {
+		return domainSlots
+	}
+
+	var requiredSlots []string
+
+	for _, slotName := range domainSlots {
+		if slotName != "company_name" {
+			requiredSlots = append(requiredSlots, slotName)
+		}
+	}
+
+	return requiredSlots
+}
+
+func businessTracker(slots map[string]interface{}, candidates ...events.Event) *rasa.Tracker {
+	return &rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName}, Slots: slots,
+		Events: append([]events.Event{&events.Action{Name: testFormName}}, candidates...)}
+}
+
+func TestDynamicRequiredSlots(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, RequiredSlots: &BusinessSlots{}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"is_business", "company_name", "email"}}}}
+
+	tracker := &rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
+		Slots: map[string]interface{}{"is_business": false, "company_name": "ACME"},
+		Events: []events.Event{
+			&events.ActiveLoop{Name: testFormName},
+			&events.SlotSet{Name: "company_name", Value: "ACME"},
+			&events.Action{Name: testFormName},
+			&events.SlotSet{Name: "is_business", Value: false},
+		}}
+	newEvents := formValidator.Run(tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{
+		&events.SlotSet{Name: "is_business", Value: false},
+		&events.SlotSet{Name: "company_name", Value: nil},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: "email"},
+	}, newEvents)
+
+	tracker = businessTracker(map[string]interface{}{"is_business": true},
+		&events.SlotSet{Name: "is_business", Value: true})
+	newEvents = formValidator.Run(tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{
+		&events.SlotSet{Name: "is_business", Value: true},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: "company_name"},
+	}, newEvents)
+
+	tracker = businessTracker(map[string]interface{}{"is_business": false, "email": "jane@example.com"},
+		&events.SlotSet{Name: "email", Value: "jane@example.com"})
+	newEvents = formValidator.Run(tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{
+		&events.SlotSet{Name: "email", Value: "jane@example.com"},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: nil},
+	}, newEvents)
+}
+
+func TestDynamicRequiredSlotsKeepSlotsFilledBeforeForm(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, RequiredSlots: &BusinessSlots{}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"is_business", "company_name", "email"}}}}
+
+	tracker := &rasa.Tracker{ActiveLoop: rasa.ActiveLoop{Name: testFormName},
+		Slots: map[string]interface{}{"is_business": false, "company_name": "ACME"},
+		Events: []events.Event{
+			&events.SlotSet{Name: "company_name", Value: "ACME"},
+			&events.ActiveLoop{Name: testFormName},
+			&events.Action{Name: testFormName},
+			&events.SlotSet{Name: "is_business", Value: false},
+		}}
+	newEvents := formValidator.Run(tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{
+		&events.SlotSet{Name: "is_business", Value: false},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: "email"},
+	}, newEvents)
+}
+
+func TestDynamicRequiredSlotsWithInvalidCandidate(t *testing.T) {
+	formValidator := FormValidationAction{FormName: testFormName, RequiredSlots: &BusinessSlots{},
+		Validators: map[string]SlotValidator{"email": &ExactMatchValidator{"jane@example.com"}}}
+	domain := rasa.Domain{Forms: map[string]map[string]interface{}{
+		testFormName: {"required_slots": []interface{}{"is_business", "company_name", "email"}}}}
+
+	tracker := businessTracker(map[string]interface{}{"is_business": false, "email": "john"},
+		&events.SlotSet{Name: "email", Value: "john"})
+	newEvents := formValidator.Run(tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{
+		&events.SlotSet{Name: "email", Value: nil},
+		&events.SlotSet{Name: rasa.RequestedSlot, Value: "email"},
+	}, newEvents)
+}
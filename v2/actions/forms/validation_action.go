@@ -0,0 +1,96 @@
+package forms
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/logging"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// ValidationActionName is the name of the action which Rasa Open Source 3 runs after every user message to extract
+// and validate slots outside of forms (https://rasa.com/docs/rasa/slot-validation-actions/#validationaction-class).
+const ValidationActionName = "action_validate_slot_mappings"
+
+// ValidationAction extracts and validates global slots, i.e. slots which are filled outside of forms
+// (Rasa Open Source 3 and later). A slot is global if it has a slot mapping which isn't restricted to a form and whose
+// conditions are met. Slots which are mapped to forms are left to the forms' validation actions. `Extractors` only
+// fill slots with a `custom` mapping. Slots are processed in lexical order.
+type ValidationAction struct {
+	// Validators specify functions to validate slot candidates.
+	Validators map[string]SlotValidator
+	// Extractors specify functions to extract slot candidates.
+	Extractors map[string]SlotExtractor
+}
+
+// Run is executed whenever Rasa Open Source sends a request to validate the slots after a user message.
+func (action *ValidationAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	tracker.Init()
+	log.WithFields(log.Fields{logging.ActionNameKey: ValidationActionName}).Debug("Validating slots.")
+
+	globalSlots := globalSlots(tracker, domain)
+
+	extractors := make(map[string]SlotExtractor, len(action.Extractors))
+	for slotName, extractor := range action.Extractors {
+		if globalSlots[slotName] && domain.Slots[slotName].HasMappingOfType(rasa.MappingCustom) {
+			extractors[slotName] = extractor
+		}
+	}
+
+	validators := make(map[string]SlotValidator, len(action.Validators))
+	for slotName, validator := range action.Validators {
+		if globalSlots[slotName] {
+			validators[slotName] = validator
+		}
+	}
+
+	validatedValues := extractAndValidate(nil, extractors, validators, tracker, domain, dispatcher)
+	for slotName := range validatedValues {
+		if !globalSlots[slotName] {
+			delete(validatedValues, slotName)
+		}
+	}
+
+	return slotEvents(nil, validatedValues)
+}
+
+// globalSlots returns the slots of the domain which can be filled outside of forms in the current state of the
+// conversation. Slots without mappings are treated as global slots.
+func globalSlots(tracker *rasa.Tracker, domain *rasa.Domain) map[string]bool {
+	global := make(map[string]bool, len(domain.Slots))
+
+	for slotName, slot := range domain.Slots {
+		if len(slot.Mappings) == 0 {
+			global[slotName] = true
+			continue
+		}
+
+		for _, mapping := range slot.Mappings {
+			if !isMappedToForm(mapping) && mapping.ConditionsMet(tracker) {
+				global[slotName] = true
+				break
+			}
+		}
+	}
+
+	return global
+}
+
+func isMappedToForm(mapping rasa.SlotMapping) bool {
+	for _, condition := range mapping.Conditions {
+		if condition.ActiveLoop != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (action *ValidationAction) Name() string { return ValidationActionName }
+
+// Metadata returns the slots which are validated or extracted by the action.
+func (action *ValidationAction) Metadata() actions.Metadata {
+	return actions.Metadata{RequiredSlots: slotNames(action.Validators, action.Extractors)}
+}
@@ -0,0 +1,76 @@
+package forms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+func TestValidationActionExtractsAndValidatesGlobalSlots(t *testing.T) {
+	validationAction := ValidationAction{
+		Validators: map[string]SlotValidator{"color": &ExactMatchValidator{"green"}},
+		Extractors: map[string]SlotExtractor{"color": &EntityExtractor{"color"}},
+	}
+	tracker := rasa.Tracker{Events: []events.Event{&events.Action{Name: "action_listen"}}}
+	tracker.LatestMessage.Entities = []events.Entity{{Name: "color", Value: "blue"}}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{"color": {Mappings: []rasa.SlotMapping{{Type: rasa.MappingCustom}}}}}
+
+	newEvents := validationAction.Run(&tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "color", Value: nil}}, newEvents)
+	assert.Equal(t, "action_validate_slot_mappings", validationAction.Name())
+	assert.Equal(t, []string{"color"}, validationAction.Metadata().RequiredSlots)
+}
+
+func TestValidationActionWithoutCandidates(t *testing.T) {
+	validationAction := ValidationAction{}
+	tracker := rasa.Tracker{Events: []events.Event{&events.Action{Name: "action_listen"}}}
+
+	newEvents := validationAction.Run(&tracker, &rasa.Domain{}, responses.NewDispatcher())
+
+	assert.Empty(t, newEvents)
+	assert.NotNil(t, newEvents)
+}
+
+func TestValidationActionSkipsSlotsOfForms(t *testing.T) {
+	validationAction := ValidationAction{
+		Validators: map[string]SlotValidator{
+			"color": &ExactMatchValidator{"green"}, "size": &ExactMatchValidator{"large"}},
+		Extractors: map[string]SlotExtractor{
+			"color": &EntityExtractor{"color"}, "size": &EntityExtractor{"size"}, "shape": &EntityExtractor{"shape"}},
+	}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{
+		"color": {Mappings: []rasa.SlotMapping{{Type: rasa.MappingCustom,
+			Conditions: []rasa.MappingCondition{{ActiveLoop: "shop_form"}}}}},
+		"size":  {Mappings: []rasa.SlotMapping{{Type: rasa.MappingFromEntity, Entity: "size"}}},
+		"shape": {Mappings: []rasa.SlotMapping{{Type: rasa.MappingFromEntity, Entity: "shape"}}},
+	}}
+	tracker := rasa.Tracker{Events: []events.Event{
+		&events.Action{Name: "action_listen"},
+		&events.SlotSet{Name: "color", Value: "blue"},
+		&events.SlotSet{Name: "size", Value: "small"},
+	}}
+	tracker.LatestMessage.Entities = []events.Entity{{Name: "shape", Value: "round"}}
+
+	newEvents := validationAction.Run(&tracker, &domain, responses.NewDispatcher())
+
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "size", Value: nil}}, newEvents)
+}
+
+func TestValidationActionWithConditionOutsideOfLoops(t *testing.T) {
+	validationAction := ValidationAction{Validators: map[string]SlotValidator{"color": &ExactMatchValidator{"green"}}}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{"color": {Mappings: []rasa.SlotMapping{
+		{Type: rasa.MappingFromText, Conditions: []rasa.MappingCondition{{ActiveLoop: ""}}}}}}}
+	tracker := rasa.Tracker{Events: []events.Event{
+		&events.Action{Name: "action_listen"}, &events.SlotSet{Name: "color", Value: "blue"}}}
+
+	newEvents := validationAction.Run(&tracker, &domain, responses.NewDispatcher())
+	assert.Equal(t, []events.Event{&events.SlotSet{Name: "color", Value: nil}}, newEvents)
+
+	tracker.ActiveLoop = rasa.ActiveLoop{Name: "shop_form"}
+	newEvents = validationAction.Run(&tracker, &domain, responses.NewDispatcher())
+	assert.Empty(t, newEvents)
+}
@@ -0,0 +1,42 @@
+package validators
+
+import (
+	"strings"
+
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+)
+
+// Bool returns a validator which accepts booleans and yes / no answers like `yes`, `sure`, `no` or `nope`. Valid
+// values are normalized to `bool`.
+func Bool(opts ...Option) forms.SlotValidator {
+	affirmations := []string{"yes", "y", "true", "1", "yeah", "yep", "sure", "ok", "okay", "of course"}
+	denials := []string{"no", "n", "false", "0", "nope", "nah", "not really"}
+
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		switch typed := value.(type) {
+		case bool:
+			return typed, true
+		case string:
+			answer := strings.ToLower(strings.Trim(typed, " .!"))
+			if contains(affirmations, answer) {
+				return true, true
+			}
+
+			if contains(denials, answer) {
+				return false, true
+			}
+		}
+
+		return nil, false
+	}, opts)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
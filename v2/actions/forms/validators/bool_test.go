@@ -0,0 +1,20 @@
+package validators
+
+import (
+	"testing"
+)
+
+func TestBool(t *testing.T) {
+	validator := Bool()
+
+	for _, answer := range []interface{}{true, "yes", "Sure!", " OK "} {
+		assertValid(t, validator, answer, true)
+	}
+
+	for _, answer := range []interface{}{false, "no", "Nope.", "not really"} {
+		assertValid(t, validator, answer, false)
+	}
+
+	assertInvalid(t, validator, "maybe")
+	assertInvalid(t, validator, 1)
+}
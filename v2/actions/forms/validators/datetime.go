@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"strings"
+	"time"
+
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date returns a validator which accepts ISO 8601 dates like `2021-03-28`. Date-times like the ones extracted by
+// Duckling (e.g. `2021-03-28T00:00:00.000+02:00`) are accepted as well. Valid values are normalized to `2021-03-28`.
+func Date(opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		text, isString := value.(string)
+		if !isString {
+			return nil, false
+		}
+
+		text = strings.TrimSpace(text)
+
+		if date, err := time.Parse(dateLayout, text); err == nil {
+			return date.Format(dateLayout), true
+		}
+
+		if dateTime, err := time.Parse(time.RFC3339, text); err == nil {
+			return dateTime.Format(dateLayout), true
+		}
+
+		return nil, false
+	}, opts)
+}
+
+// DateTime returns a validator which accepts ISO 8601 date-times with time zone like `2021-03-28T18:30:00+02:00`.
+// Valid values are normalized to RFC 3339 without fractional seconds.
+func DateTime(opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		text, isString := value.(string)
+		if !isString {
+			return nil, false
+		}
+
+		dateTime, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, false
+		}
+
+		return dateTime.Format(time.RFC3339), true
+	}, opts)
+}
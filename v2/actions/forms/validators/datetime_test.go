@@ -0,0 +1,25 @@
+package validators
+
+import (
+	"testing"
+)
+
+func TestDate(t *testing.T) {
+	validator := Date()
+
+	assertValid(t, validator, "2021-03-28", "2021-03-28")
+	assertValid(t, validator, "2021-03-28T00:00:00.000+02:00", "2021-03-28")
+	assertInvalid(t, validator, "2021-02-30")
+	assertInvalid(t, validator, "28.03.2021")
+	assertInvalid(t, validator, 20210328)
+}
+
+func TestDateTime(t *testing.T) {
+	validator := DateTime()
+
+	assertValid(t, validator, "2021-03-28T18:30:00.000+02:00", "2021-03-28T18:30:00+02:00")
+	assertValid(t, validator, "2021-03-28T16:30:00Z", "2021-03-28T16:30:00Z")
+	assertInvalid(t, validator, "2021-03-28")
+	assertInvalid(t, validator, "2021-03-28 18:30")
+	assertInvalid(t, validator, nil)
+}
@@ -0,0 +1,32 @@
+package validators
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+)
+
+// Range returns a validator which accepts numbers within `[min, max]`. Strings which contain numbers are accepted
+// as well. Valid values are normalized to `float64`. Use `math.Inf` for ranges without lower or upper bound.
+func Range(min, max float64, opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		number, isNumber := rasa.AsFloat(value)
+		if !isNumber || number < min || number > max {
+			return nil, false
+		}
+
+		return number, true
+	}, opts)
+}
+
+// Integer returns a validator which accepts whole numbers within `[min, max]`. Strings which contain whole numbers
+// are accepted as well. Valid values are normalized to `int`.
+func Integer(min, max int, opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		number, isNumber := rasa.AsInt(value)
+		if !isNumber || number < min || number > max {
+			return nil, false
+		}
+
+		return number, true
+	}, opts)
+}
@@ -0,0 +1,36 @@
+package validators
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	validator := Range(0.5, 10)
+
+	assertValid(t, validator, 0.5, 0.5)
+	assertValid(t, validator, 10, 10.0)
+	assertValid(t, validator, " 2.5 ", 2.5)
+	assertValid(t, validator, json.Number("3"), 3.0)
+	assertInvalid(t, validator, 10.5)
+	assertInvalid(t, validator, "NaN")
+	assertInvalid(t, validator, "five")
+	assertInvalid(t, validator, true)
+
+	assertValid(t, Range(0, math.Inf(1)), 1e12, 1e12)
+}
+
+func TestInteger(t *testing.T) {
+	validator := Integer(1, 20)
+
+	assertValid(t, validator, 4.0, 4)
+	assertValid(t, validator, "20", 20)
+	assertInvalid(t, validator, 2.5)
+	assertInvalid(t, validator, 0)
+	assertInvalid(t, validator, "21")
+
+	assertValid(t, Integer(1, math.MaxInt), json.Number("42"), 42)
+	assertInvalid(t, Integer(1, math.MaxInt), math.Pow(2, 63))
+	assertInvalid(t, Integer(math.MinInt, 0), -math.Pow(2, 64))
+}
@@ -0,0 +1,131 @@
+package validators
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+)
+
+// Enum returns a validator which accepts the given values. Values are compared case-insensitively unless the
+// `CaseSensitive` option is used. The valid value is normalized to the spelling of `values`.
+func Enum(values []string, opts ...Option) forms.SlotValidator {
+	v := newValidator(nil, opts)
+	v.check = func(value interface{}) (interface{}, bool) {
+		candidate := strings.TrimSpace(fmt.Sprint(value))
+
+		for _, valid := range values {
+			if candidate == valid || (!v.options.caseSensitive && strings.EqualFold(candidate, valid)) {
+				return valid, true
+			}
+		}
+
+		return nil, false
+	}
+
+	return v
+}
+
+// Regex returns a validator which accepts strings matching the regular expression. Leading and trailing whitespace
+// is removed from valid values.
+func Regex(expression *regexp.Regexp, opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		text, isString := value.(string)
+		if !isString {
+			return nil, false
+		}
+
+		text = strings.TrimSpace(text)
+
+		return text, expression.MatchString(text)
+	}, opts)
+}
+
+// Email returns a validator which accepts email addresses like `jane@example.com`. The domain of valid addresses
+// is converted to lower case.
+func Email(opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		text, isString := value.(string)
+		if !isString {
+			return nil, false
+		}
+
+		text = strings.TrimSpace(text)
+
+		address, err := mail.ParseAddress(text)
+		if err != nil || address.Address != text {
+			return nil, false
+		}
+
+		at := strings.LastIndex(text, "@")
+		domain := strings.ToLower(text[at+1:])
+
+		if !strings.Contains(domain, ".") {
+			return nil, false
+		}
+
+		return text[:at+1] + domain, true
+	}, opts)
+}
+
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// Phone returns a validator which accepts phone numbers with 7 to 15 digits and an optional leading `+`. Spaces,
+// dashes, dots, slashes and parentheses are removed from valid numbers, e.g. `+49 (30) 123-456` becomes
+// `+4930123456`.
+func Phone(opts ...Option) forms.SlotValidator {
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		text, isString := value.(string)
+		if !isString {
+			return nil, false
+		}
+
+		text = strings.TrimSpace(text)
+
+		var normalized strings.Builder
+		digits := 0
+
+		for i, character := range text {
+			switch {
+			case unicode.IsDigit(character):
+				normalized.WriteRune(character)
+				digits++
+			case character == '+' && i == 0:
+				normalized.WriteRune(character)
+			case strings.ContainsRune(" -./()", character):
+				continue
+			default:
+				return nil, false
+			}
+		}
+
+		return normalized.String(), digits >= minPhoneDigits && digits <= maxPhoneDigits
+	}, opts)
+}
+
+// Length returns a validator which accepts strings and lists whose length is within `[min, max]`. The length of
+// strings is the number of characters after removing leading and trailing whitespace.
+func Length(min, max int, opts ...Option) forms.SlotValidator {
+	inRange := func(length int) bool { return length >= min && length <= max }
+
+	return newValidator(func(value interface{}) (interface{}, bool) {
+		switch typed := value.(type) {
+		case string:
+			trimmed := strings.TrimSpace(typed)
+			return trimmed, inRange(utf8.RuneCountInString(trimmed))
+		case []interface{}:
+			return typed, inRange(len(typed))
+		case []string:
+			return typed, inRange(len(typed))
+		default:
+			return nil, false
+		}
+	}, opts)
+}
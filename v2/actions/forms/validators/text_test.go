@@ -0,0 +1,58 @@
+package validators
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	validator := Enum([]string{"Italian", "Chinese"})
+
+	assertValid(t, validator, "italian", "Italian")
+	assertValid(t, validator, " CHINESE ", "Chinese")
+	assertInvalid(t, validator, "greek")
+
+	caseSensitive := Enum([]string{"Italian"}, CaseSensitive())
+	assertValid(t, caseSensitive, "Italian", "Italian")
+	assertInvalid(t, caseSensitive, "italian")
+}
+
+func TestRegex(t *testing.T) {
+	validator := Regex(regexp.MustCompile(`^\d{5}$`))
+
+	assertValid(t, validator, " 10115", "10115")
+	assertInvalid(t, validator, "1011")
+	assertInvalid(t, validator, 10115)
+}
+
+func TestEmail(t *testing.T) {
+	validator := Email()
+
+	assertValid(t, validator, "jane.doe@Example.COM ", "jane.doe@example.com")
+	assertInvalid(t, validator, "jane.doe")
+	assertInvalid(t, validator, "jane@localhost")
+	assertInvalid(t, validator, "Jane <jane@example.com>")
+	assertInvalid(t, validator, 42)
+}
+
+func TestPhone(t *testing.T) {
+	validator := Phone()
+
+	assertValid(t, validator, "+49 (30) 123-456", "+4930123456")
+	assertValid(t, validator, "030/1234567", "0301234567")
+	assertInvalid(t, validator, "12345")
+	assertInvalid(t, validator, "1234567890123456")
+	assertInvalid(t, validator, "030 123 4567 ext")
+	assertInvalid(t, validator, "030+1234567")
+}
+
+func TestLength(t *testing.T) {
+	validator := Length(2, 4)
+
+	assertValid(t, validator, " äöü ", "äöü")
+	assertValid(t, validator, []interface{}{"a", "b"}, []interface{}{"a", "b"})
+	assertValid(t, validator, []string{"a", "b", "c", "d"}, []string{"a", "b", "c", "d"})
+	assertInvalid(t, validator, "a")
+	assertInvalid(t, validator, "abcde")
+	assertInvalid(t, validator, 123)
+}
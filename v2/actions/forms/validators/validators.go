@@ -0,0 +1,126 @@
+// Package validators provides configurable `forms.SlotValidator`s for common slot types, e.g. choices, numbers,
+// email addresses or dates. Validators normalize valid values, e.g. they convert numbers sent as strings to numbers.
+// Use `Utter` to send a response to the user in case a value is invalid and `All` / `Any` to combine validators.
+package validators
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// Option configures a validator.
+type Option func(*options)
+
+type options struct {
+	template      string
+	caseSensitive bool
+}
+
+// Utter sends the response with the given name from the domain to the user in case the value is invalid.
+func Utter(template string) Option {
+	return func(o *options) { o.template = template }
+}
+
+// CaseSensitive compares strings case-sensitively. Strings are compared case-insensitively by default.
+func CaseSensitive() Option {
+	return func(o *options) { o.caseSensitive = true }
+}
+
+// CheckFunc checks a slot candidate. It returns the normalized value and `true` if the value is valid.
+type CheckFunc func(value interface{}) (normalizedValue interface{}, isValid bool)
+
+type validator struct {
+	check   CheckFunc
+	options options
+}
+
+func newValidator(check CheckFunc, opts []Option) *validator {
+	v := &validator{check: check}
+	for _, option := range opts {
+		option(&v.options)
+	}
+
+	return v
+}
+
+// Func returns a validator which checks values using the given function. `nil` values are always invalid.
+func Func(check CheckFunc, opts ...Option) forms.SlotValidator {
+	return newValidator(check, opts)
+}
+
+func (v *validator) IsValid(value interface{}, _ *rasa.Domain, _ *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) (interface{}, bool) {
+	if value != nil {
+		if normalized, isValid := v.check(value); isValid {
+			return normalized, true
+		}
+	}
+
+	if v.options.template != "" {
+		dispatcher.Utter(&responses.Message{Template: v.options.template})
+	}
+
+	return nil, false
+}
+
+type allValidator struct {
+	validators []forms.SlotValidator
+}
+
+// All returns a validator which accepts a value if all validators accept it. The value normalized by a validator is
+// passed to the next validator. The validation stops at the first validator which rejects the value.
+func All(validators ...forms.SlotValidator) forms.SlotValidator {
+	return &allValidator{validators}
+}
+
+func (v *allValidator) IsValid(value interface{}, domain *rasa.Domain, tracker *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) (interface{}, bool) {
+	for _, validator := range v.validators {
+		validated, isValid := validator.IsValid(value, domain, tracker, dispatcher)
+		if !isValid {
+			return nil, false
+		}
+
+		value = validated
+	}
+
+	return value, true
+}
+
+type anyValidator struct {
+	validators []forms.SlotValidator
+}
+
+// Any returns a validator which accepts a value if one of the validators accepts it. The value normalized by the
+// first validator which accepts it is used. Responses of validators which rejected the value are only sent to the
+// user if none of the validators accepts the value.
+func Any(validators ...forms.SlotValidator) forms.SlotValidator {
+	return &anyValidator{validators}
+}
+
+func (v *anyValidator) IsValid(value interface{}, domain *rasa.Domain, tracker *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) (interface{}, bool) {
+	rejections := responses.NewDispatcher()
+
+	for _, validator := range v.validators {
+		attempt := responses.NewDispatcher()
+		if validated, isValid := validator.IsValid(value, domain, tracker, attempt); isValid {
+			forward(attempt, dispatcher)
+			return validated, true
+		}
+
+		forward(attempt, rejections)
+	}
+
+	forward(rejections, dispatcher)
+
+	return nil, false
+}
+
+// forward sends the messages dispatched to `from` with the dispatcher `to`.
+func forward(from, to responses.ResponseDispatcher) {
+	for _, message := range from.Responses() {
+		to.Utter(message)
+	}
+}
@@ -0,0 +1,85 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+func validate(validator forms.SlotValidator, value interface{}) (interface{}, bool, []*responses.Message) {
+	dispatcher := responses.NewDispatcher()
+	validated, isValid := validator.IsValid(value, &rasa.Domain{}, &rasa.Tracker{}, dispatcher)
+
+	return validated, isValid, dispatcher.Responses()
+}
+
+func assertValid(t *testing.T, validator forms.SlotValidator, value, expected interface{}) {
+	t.Helper()
+
+	validated, isValid, _ := validate(validator, value)
+	assert.True(t, isValid, "%v should be valid", value)
+	assert.Equal(t, expected, validated)
+}
+
+func assertInvalid(t *testing.T, validator forms.SlotValidator, value interface{}) {
+	t.Helper()
+
+	validated, isValid, _ := validate(validator, value)
+	assert.False(t, isValid, "%v should be invalid", value)
+	assert.Nil(t, validated)
+}
+
+func TestFunc(t *testing.T) {
+	positive := Func(func(value interface{}) (interface{}, bool) {
+		number, ok := value.(int)
+		return number, ok && number > 0
+	})
+
+	assertValid(t, positive, 5, 5)
+	assertInvalid(t, positive, -1)
+	assertInvalid(t, positive, nil)
+}
+
+func TestUtterOnFailure(t *testing.T) {
+	validator := Enum([]string{"green"}, Utter("utter_wrong_color"))
+
+	_, isValid, messages := validate(validator, "blue")
+	assert.False(t, isValid)
+	assert.Equal(t, []*responses.Message{{Template: "utter_wrong_color"}}, messages)
+
+	_, isValid, messages = validate(validator, "green")
+	assert.True(t, isValid)
+	assert.Empty(t, messages)
+
+	_, _, messages = validate(Enum([]string{"green"}), "blue")
+	assert.Empty(t, messages)
+}
+
+func TestAll(t *testing.T) {
+	validator := All(Integer(1, 10), Func(func(value interface{}) (interface{}, bool) {
+		return value.(int) * 2, value.(int)%2 == 0
+	}, Utter("utter_not_even")))
+
+	assertValid(t, validator, "4", 8)
+	assertInvalid(t, validator, "12")
+
+	_, isValid, messages := validate(validator, 3)
+	assert.False(t, isValid)
+	assert.Equal(t, []*responses.Message{{Template: "utter_not_even"}}, messages)
+}
+
+func TestAny(t *testing.T) {
+	validator := Any(Email(Utter("utter_wrong_email")), Phone(Utter("utter_wrong_phone")))
+
+	validated, isValid, messages := validate(validator, "+49 30 1234567")
+	assert.True(t, isValid)
+	assert.Equal(t, "+49301234567", validated)
+	assert.Empty(t, messages)
+
+	_, isValid, messages = validate(validator, "call me maybe")
+	assert.False(t, isValid)
+	assert.Equal(t, []*responses.Message{{Template: "utter_wrong_email"}, {Template: "utter_wrong_phone"}}, messages)
+}
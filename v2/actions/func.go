@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// RunFunc is the signature of the `Run` method of an `Action`.
+type RunFunc func(tracker *rasa.Tracker, domain *rasa.Domain, dispatcher responses.ResponseDispatcher) []events.Event
+
+// ActionFunc turns a function into an `Action` with the given name.
+func ActionFunc(name string, run RunFunc) Action {
+	return &funcAction{name: name, run: run}
+}
+
+type funcAction struct {
+	name string
+	run  RunFunc
+}
+
+func (action *funcAction) Run(tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	return action.run(tracker, domain, dispatcher)
+}
+
+func (action *funcAction) Name() string { return action.name }
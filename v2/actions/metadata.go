@@ -0,0 +1,56 @@
+package actions
+
+import (
+	"reflect"
+)
+
+// Metadata describes an action, e.g. for deployment checks.
+type Metadata struct {
+	// Description is a human readable description of what the action does.
+	Description string `json:"description,omitempty"`
+	// RequiredSlots are the slots which the action works with.
+	RequiredSlots []string `json:"required_slots,omitempty"`
+	// FormName is the name of the form which the action validates.
+	FormName string `json:"form_name,omitempty"`
+}
+
+// Describer can be implemented by actions to provide metadata about themselves.
+type Describer interface {
+	// Metadata returns the metadata of the action.
+	Metadata() Metadata
+}
+
+// MetadataOf returns the metadata of an action in case it or the action wrapped by `Contextual` or `Fallible`
+// implements `Describer`.
+func MetadataOf(action Action) Metadata {
+	if describer, ok := unwrap(action).(Describer); ok {
+		return describer.Metadata()
+	}
+
+	return Metadata{}
+}
+
+// unwrap returns the action which was passed to one of the adapters of this package.
+func unwrap(action Action) interface{} {
+	switch adapted := action.(type) {
+	case *contextualAction:
+		return adapted.ContextAction
+	case *fallibleAction:
+		return adapted.FallibleAction
+	default:
+		return action
+	}
+}
+
+func (action *slotAction[In, Out]) Metadata() Metadata {
+	var slots []string
+
+	inputType := reflect.TypeOf((*In)(nil)).Elem()
+	for i := 0; i < inputType.NumField(); i++ {
+		if field, ok := slotFieldFor(inputType.Field(i)); ok {
+			slots = append(slots, field.slotName)
+		}
+	}
+
+	return Metadata{RequiredSlots: slots}
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// Invocation describes a single execution of a custom action. Middleware can modify the tracker and the domain before
+// the action is run and replace the dispatcher, e.g. to filter the messages of the action. The messages dispatched to
+// `Dispatcher` are sent to the user.
+type Invocation struct {
+	// ActionName is the name of the action which is executed.
+	ActionName string
+	// Tracker is the conversation tracker which is passed to the action.
+	Tracker *rasa.Tracker
+	// Domain is the domain which is passed to the action.
+	Domain *rasa.Domain
+	// Dispatcher is the dispatcher which is passed to the action.
+	Dispatcher responses.ResponseDispatcher
+}
+
+// ActionHandler executes an invocation of an action and returns the new conversation events or an error.
+type ActionHandler func(ctx context.Context, invocation *Invocation) ([]events.Event, error)
+
+// Middleware wraps the execution of actions, e.g. to add audit logging or feature flag checks. A middleware can
+// inspect and modify the invocation and the returned events, or short-circuit the execution by not calling `next`
+// and returning an error (e.g. `Reject`) instead.
+type Middleware func(next ActionHandler) ActionHandler
+
+// WithMiddleware registers middleware which wraps the execution of all actions. Middleware is called in the order
+// it was registered, i.e. the first middleware is the outermost one. Global middleware wraps the middleware which was
+// registered for specific actions.
+func WithMiddleware(middleware ...Middleware) ExecutionOption {
+	return func(config *executionConfig) { config.middleware = append(config.middleware, middleware...) }
+}
+
+// WithActionMiddleware registers middleware which only wraps the execution of the action with the given name.
+func WithActionMiddleware(actionName string, middleware ...Middleware) ExecutionOption {
+	return func(config *executionConfig) {
+		config.actionMiddleware[actionName] = append(config.actionMiddleware[actionName], middleware...)
+	}
+}
+
+// handlerFor returns the handler which runs the action wrapped by the middleware which applies to it.
+func (config *executionConfig) handlerFor(action Action) ActionHandler {
+	var handler ActionHandler = func(ctx context.Context, invocation *Invocation) ([]events.Event, error) {
+		return runWithTracing(ctx, action, invocation)
+	}
+
+	handler = chain(handler, config.actionMiddleware[action.Name()])
+
+	return chain(handler, config.middleware)
+}
+
+func chain(handler ActionHandler, middleware []Middleware) ActionHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, invocation *Invocation) ([]events.Event, error) {
+			*calls = append(*calls, name+" before")
+			newEvents, err := next(ctx, invocation)
+			*calls = append(*calls, name+" after")
+
+			return newEvents, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-action"}
+
+	_, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{&SimpleTestAction{}},
+		WithActionMiddleware("test-action", recordingMiddleware("action", &calls)),
+		WithMiddleware(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls)),
+		WithActionMiddleware("other-action", recordingMiddleware("other", &calls)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first before", "second before", "action before", "action after", "second after",
+		"first after"}, calls)
+}
+
+func TestMiddlewareModifiesEvents(t *testing.T) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-action"}
+	addRestart := func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, invocation *Invocation) ([]events.Event, error) {
+			newEvents, err := next(ctx, invocation)
+			return append(newEvents, &events.Restarted{}), err
+		}
+	}
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{&SimpleTestAction{}},
+		WithMiddleware(addRestart))
+
+	assert.Nil(t, err)
+	assert.Len(t, response["events"], 2)
+}
+
+type TrackerReadingAction struct{}
+
+func (action *TrackerReadingAction) Run(tracker *rasa.Tracker, _ *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	dispatcher.Utter(&responses.Message{Text: "sender " + tracker.ConversationID})
+
+	return []events.Event{}
+}
+func (action *TrackerReadingAction) Name() string { return "test-tracker-reading" }
+
+func TestMiddlewareModifiesTrackerAndResponses(t *testing.T) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-tracker-reading",
+		Tracker: rasa.Tracker{ConversationID: "alice@example.com"}}
+	scrubbing := func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, invocation *Invocation) ([]events.Event, error) {
+			invocation.Tracker.ConversationID = "anonymous"
+
+			dispatcher := invocation.Dispatcher
+			invocation.Dispatcher = responses.NewDispatcher()
+			newEvents, err := next(ctx, invocation)
+
+			for _, message := range invocation.Dispatcher.Responses() {
+				dispatcher.Utter(&responses.Message{Text: message.Text + "!"})
+			}
+			invocation.Dispatcher = dispatcher
+
+			return newEvents, err
+		}
+	}
+
+	response, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{&TrackerReadingAction{}},
+		WithMiddleware(scrubbing))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*responses.Message{{Text: "sender anonymous!"}}, response["responses"])
+}
+
+func TestMiddlewareShortCircuitsWithRejection(t *testing.T) {
+	actionRequest := request.CustomActionRequest{ActionToRun: "test-action"}
+	featureFlag := func(next ActionHandler) ActionHandler {
+		return func(ctx context.Context, invocation *Invocation) ([]events.Event, error) {
+			return nil, Reject("feature disabled")
+		}
+	}
+
+	_, err := ExecuteActionContext(context.Background(), &actionRequest, []Action{&SimpleTestAction{}},
+		WithActionMiddleware("test-action", featureFlag))
+
+	assert.IsType(t, &ExecutionRejectedError{}, err)
+	assert.Equal(t, "action 'test-action' rejected execution: feature disabled.", err.Error())
+}
@@ -0,0 +1,30 @@
+package actions
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+)
+
+// eventsForProtocol translates the events returned by an action into the events which the protocol of the request
+// expects so that the same action can serve Rasa Open Source 1 and Rasa Open Source 2 and later.
+func eventsForProtocol(actionRequest *request.CustomActionRequest, newEvents []events.Event) []events.Event {
+	if actionRequest.Protocol == request.ProtocolActiveForm {
+		return events.AsFormEvents(newEvents)
+	}
+
+	return events.AsLoopEvents(newEvents)
+}
+
+// rasaVersion returns the major version of Rasa Open Source which sent the request. Requests using the protocol of
+// Rasa Open Source 1 are answered like Rasa Open Source 1 requests even if they don't contain a version.
+func rasaVersion(actionRequest *request.CustomActionRequest) int {
+	if version := actionRequest.MajorVersion(); version > 0 {
+		return version
+	}
+
+	if actionRequest.Protocol == request.ProtocolActiveForm {
+		return 1
+	}
+
+	return 0
+}
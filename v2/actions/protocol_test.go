@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+type StopFormAction struct{}
+
+func (action *StopFormAction) Run(tracker *rasa.Tracker, _ *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) []events.Event {
+	if tracker.ActiveLoop.Name == "" {
+		return []events.Event{}
+	}
+
+	dispatcher.Utter(&responses.Message{Template: "utter_form_stopped"})
+
+	return []events.Event{&events.LoopInterrupted{IsInterrupted: true}, &events.ActiveLoop{}}
+}
+func (action *StopFormAction) Name() string { return "action_stop_form" }
+
+func TestExecuteActionForBothProtocols(t *testing.T) {
+	requests := map[string]string{
+		`{"next_action": "action_stop_form", "tracker": {"active_form": {"name": "restaurant_form"}}}`: `{"events":[` +
+			`{"event":"form_validation","validate":false},{"event":"form"}],` +
+			`"responses":[{"text":"","template":"utter_form_stopped"}]}`,
+		`{"next_action": "action_stop_form", "tracker": {"active_loop": {"name": "restaurant_form"}}}`: `{"events":[` +
+			`{"event":"loop_interrupted","is_interrupted":true},{"event":"active_loop"}],` +
+			`"responses":[{"text":"","template":"utter_form_stopped"}]}`,
+	}
+
+	for body, expected := range requests {
+		actionRequest, err := request.Parsed(strings.NewReader(body))
+		assert.Nil(t, err)
+
+		response, err := ExecuteAction(&actionRequest, []Action{&StopFormAction{}})
+		assert.Nil(t, err)
+
+		serialized, err := json.Marshal(response)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(serialized))
+	}
+}
@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+)
+
+// DuplicateActionError happens when an action is registered with a name which is already taken by another action.
+type DuplicateActionError struct{ name string }
+
+func (e *DuplicateActionError) Error() string {
+	return fmt.Sprintf("action '%s' is registered more than once.", e.name)
+}
+
+// Registry indexes custom actions by their name. Actions can be registered and unregistered while the registry is
+// used to execute actions. The zero value is an empty registry which is ready to use.
+type Registry struct {
+	mutex   sync.RWMutex
+	actions map[string]Action
+}
+
+// NewRegistry returns a registry which contains the given actions. A `DuplicateActionError` is returned if two
+// actions have the same name.
+func NewRegistry(customActions ...Action) (*Registry, error) {
+	registry := &Registry{}
+	if err := registry.Register(customActions...); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Register adds the given actions to the registry. A `DuplicateActionError` is returned and none of the actions are
+// added if an action with the same name is already registered or if two of the given actions have the same name.
+func (registry *Registry) Register(customActions ...Action) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	added := make(map[string]Action, len(customActions))
+
+	for _, action := range customActions {
+		name := action.Name()
+		if _, registered := registry.actions[name]; registered {
+			return &DuplicateActionError{name: name}
+		}
+
+		if _, duplicate := added[name]; duplicate {
+			return &DuplicateActionError{name: name}
+		}
+
+		added[name] = action
+	}
+
+	if registry.actions == nil {
+		registry.actions = make(map[string]Action, len(added))
+	}
+
+	for name, action := range added {
+		registry.actions[name] = action
+	}
+
+	return nil
+}
+
+// Unregister removes the actions with the given names from the registry. Unknown names are ignored.
+func (registry *Registry) Unregister(names ...string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, name := range names {
+		delete(registry.actions, name)
+	}
+}
+
+// Lookup returns the action with the given name or `nil` if no such action is registered.
+func (registry *Registry) Lookup(name string) Action {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.actions[name]
+}
+
+// Actions returns the registered actions sorted by their name.
+func (registry *Registry) Actions() []Action {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	registered := make([]Action, 0, len(registry.actions))
+	for _, action := range registry.actions {
+		registered = append(registered, action)
+	}
+
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Name() < registered[j].Name() })
+
+	return registered
+}
+
+// Execute executes the registered custom action which was requested by Rasa Open Source.
+// The given context is passed to actions which implement `ContextAction`.
+func (registry *Registry) Execute(ctx context.Context, actionRequest *request.CustomActionRequest,
+	options ...ExecutionOption) (map[string]interface{}, error) {
+	return execute(ctx, actionRequest, registry.Lookup, options)
+}
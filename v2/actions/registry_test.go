@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+type NamedAction struct{ name string }
+
+func (action *NamedAction) Run(_ *rasa.Tracker, _ *rasa.Domain, _ responses.ResponseDispatcher) []events.Event {
+	return []events.Event{&events.SlotSet{Name: "executed", Value: action.name}}
+}
+func (action *NamedAction) Name() string { return action.name }
+
+func TestRegistryLookup(t *testing.T) {
+	first, second := &NamedAction{"first"}, &NamedAction{"second"}
+
+	registry, err := NewRegistry(first, second)
+
+	assert.Nil(t, err)
+	assert.Equal(t, second, registry.Lookup("second"))
+	assert.Nil(t, registry.Lookup("unknown"))
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	_, err := NewRegistry(&NamedAction{"first"}, &NamedAction{"first"})
+
+	assert.IsType(t, &DuplicateActionError{}, err)
+	assert.Equal(t, "action 'first' is registered more than once.", err.Error())
+}
+
+func TestRegistryRegisterIsAtomic(t *testing.T) {
+	registry, _ := NewRegistry(&NamedAction{"existing"})
+
+	err := registry.Register(&NamedAction{"new"}, &NamedAction{"existing"})
+
+	assert.IsType(t, &DuplicateActionError{}, err)
+	assert.Nil(t, registry.Lookup("new"))
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	registry, _ := NewRegistry(&NamedAction{"first"}, &NamedAction{"second"})
+
+	registry.Unregister("first", "unknown")
+
+	assert.Nil(t, registry.Lookup("first"))
+	assert.Nil(t, registry.Register(&NamedAction{"first"}))
+	assert.NotNil(t, registry.Lookup("first"))
+}
+
+func TestRegistryActionsAreSorted(t *testing.T) {
+	registry := &Registry{}
+	assert.Empty(t, registry.Actions())
+
+	_ = registry.Register(&NamedAction{"c"}, &NamedAction{"a"}, &NamedAction{"b"})
+
+	var names []string
+	for _, action := range registry.Actions() {
+		names = append(names, action.Name())
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+}
+
+func TestRegistryExecute(t *testing.T) {
+	registry, _ := NewRegistry(&NamedAction{"first"}, &NamedAction{"second"})
+
+	response, err := registry.Execute(context.Background(), &request.CustomActionRequest{ActionToRun: "second"})
+
+	assert.Nil(t, err)
+	assert.Len(t, response["events"], 1)
+
+	_, err = registry.Execute(context.Background(), &request.CustomActionRequest{ActionToRun: "unknown"})
+
+	assert.IsType(t, &NotFoundError{}, err)
+}
+
+func TestExecuteActionRunsFirstOfDuplicateActions(t *testing.T) {
+	first, duplicate := &NamedAction{"first"}, &NamedAction{"first"}
+
+	assert.Same(t, first, actionFor("first", []Action{first, duplicate}))
+
+	response, err := ExecuteAction(&request.CustomActionRequest{ActionToRun: "first"}, []Action{first, duplicate})
+
+	assert.Nil(t, err)
+	assert.Len(t, response["events"], 1)
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	registry := &Registry{}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("action-%d", i)
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, registry.Register(&NamedAction{name}))
+			registry.Unregister(name)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = registry.Execute(context.Background(), &request.CustomActionRequest{ActionToRun: name})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Empty(t, registry.Actions())
+}
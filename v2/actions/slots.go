@@ -0,0 +1,188 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+// slotTag is the struct tag which maps struct fields to slots, e.g. `slot:"departure_city"`. Fields of result
+// structs can use `slot:"name,omitempty"` to skip setting the slot if the field has its zero value.
+const slotTag = "slot"
+
+// SlotFunc is the signature of functions which are used by `SlotAction`. It receives the slot values bound to `In`
+// and returns the slot values which should be set.
+type SlotFunc[In, Out any] func(ctx context.Context, slots In, tracker *rasa.Tracker,
+	dispatcher responses.ResponseDispatcher) (Out, error)
+
+// SlotAction returns an action with the given name which binds slots of the tracker to the fields of the struct `In`
+// before calling `run`. Fields are mapped to slots using the `slot` struct tag. The fields of the returned `Out`
+// struct which have a `slot` struct tag are turned into `events.SlotSet` events.
+// A `BadInputError` is returned when a slot value cannot be converted to the type of its field. An `InternalError`
+// is returned when the type of a field doesn't match the slot type in the domain.
+// SlotAction panics if `In` or `Out` are not structs.
+func SlotAction[In, Out any](name string, run SlotFunc[In, Out]) Action {
+	mustBeStruct(reflect.TypeOf((*In)(nil)).Elem())
+	mustBeStruct(reflect.TypeOf((*Out)(nil)).Elem())
+
+	return Fallible(&slotAction[In, Out]{name: name, run: run})
+}
+
+func mustBeStruct(structType reflect.Type) {
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("slot bindings require a struct but got '%v'", structType))
+	}
+}
+
+type slotAction[In, Out any] struct {
+	name string
+	run  SlotFunc[In, Out]
+}
+
+func (action *slotAction[In, Out]) RunWithError(ctx context.Context, tracker *rasa.Tracker, domain *rasa.Domain,
+	dispatcher responses.ResponseDispatcher) ([]events.Event, error) {
+	var slots In
+	if err := bindSlots(tracker, domain, reflect.ValueOf(&slots).Elem()); err != nil {
+		return nil, err
+	}
+
+	result, err := action.run(ctx, slots, tracker, dispatcher)
+	if err != nil {
+		return nil, err
+	}
+
+	return slotEvents(reflect.ValueOf(result)), nil
+}
+
+func (action *slotAction[In, Out]) Name() string { return action.name }
+
+type slotField struct {
+	slotName  string
+	omitEmpty bool
+}
+
+func slotFieldFor(field reflect.StructField) (slotField, bool) {
+	tag, ok := field.Tag.Lookup(slotTag)
+	if !ok || tag == "-" || !field.IsExported() {
+		return slotField{}, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+
+	return slotField{slotName: name, omitEmpty: options == "omitempty"}, true
+}
+
+// bindSlots sets the fields of the struct `target` to the values of the slots they are mapped to.
+func bindSlots(tracker *rasa.Tracker, domain *rasa.Domain, target reflect.Value) error {
+	for i := 0; i < target.NumField(); i++ {
+		field, ok := slotFieldFor(target.Type().Field(i))
+		if !ok {
+			continue
+		}
+
+		if slot, declared := domain.Slots[field.slotName]; declared && !isCompatible(slot.Type, target.Field(i).Type()) {
+			return Internal(fmt.Errorf("field for slot '%s' has type '%v' which doesn't match slot type '%s'",
+				field.slotName, target.Field(i).Type(), slot.Type))
+		}
+
+		value, ok := tracker.Slots[field.slotName]
+		if !ok || value == nil {
+			continue
+		}
+
+		if err := convertSlot(value, target.Field(i)); err != nil {
+			return BadInput(fmt.Errorf("slot '%s' has invalid value '%v': %w", field.slotName, value, err))
+		}
+	}
+
+	return nil
+}
+
+// convertSlot converts the slot value as it was received from Rasa Open Source to the type of the field.
+func convertSlot(value interface{}, field reflect.Value) error {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	converted := reflect.New(field.Type())
+	if err := json.Unmarshal(serialized, converted.Interface()); err != nil {
+		return err
+	}
+
+	field.Set(converted.Elem())
+
+	return nil
+}
+
+// isCompatible checks if a field of the given type can hold values of a slot with the given type. Slot types can be
+// given as in the `domain.yml` or as the class paths which Rasa Open Source sends.
+func isCompatible(slotType string, fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType.Kind() == reflect.Interface {
+		return true
+	}
+
+	switch rasa.SlotTypeName(slotType) {
+	case "text":
+		return fieldType.Kind() == reflect.String
+	case "categorical":
+		// Categories can be strings, numbers or booleans.
+		return fieldType.Kind() == reflect.String || fieldType.Kind() == reflect.Bool || isNumber(fieldType)
+	case "bool":
+		return fieldType.Kind() == reflect.Bool
+	case "float":
+		return isNumber(fieldType)
+	case "list":
+		return fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array
+	default:
+		return true
+	}
+}
+
+func isNumber(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// slotEvents returns `SlotSet` events for the fields of the struct `result` which are mapped to slots.
+func slotEvents(result reflect.Value) []events.Event {
+	newEvents := []events.Event{}
+
+	for i := 0; i < result.NumField(); i++ {
+		field, ok := slotFieldFor(result.Type().Field(i))
+		if !ok || (field.omitEmpty && result.Field(i).IsZero()) {
+			continue
+		}
+
+		newEvents = append(newEvents, &events.SlotSet{Name: field.slotName, Value: slotValue(result.Field(i))})
+	}
+
+	return newEvents
+}
+
+func slotValue(field reflect.Value) interface{} {
+	if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface) && field.IsNil() {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		return field.Elem().Interface()
+	}
+
+	return field.Interface()
+}
@@ -0,0 +1,179 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+func TestActionFunc(t *testing.T) {
+	action := ActionFunc("test-func", func(tracker *rasa.Tracker, _ *rasa.Domain,
+		dispatcher responses.ResponseDispatcher) []events.Event {
+		dispatcher.Utter(&responses.Message{Text: "hello " + tracker.ConversationID})
+		return []events.Event{&events.Restarted{}}
+	})
+
+	dispatcher := responses.NewDispatcher()
+	newEvents := action.Run(&rasa.Tracker{ConversationID: "alice"}, &rasa.Domain{}, dispatcher)
+
+	assert.Equal(t, "test-func", action.Name())
+	assert.Equal(t, []events.Event{&events.Restarted{}}, newEvents)
+	assert.Equal(t, []*responses.Message{{Text: "hello alice"}}, dispatcher.Responses())
+}
+
+type flightSearch struct {
+	From       string   `slot:"departure_city"`
+	Passengers int      `slot:"passengers"`
+	Return     *bool    `slot:"return_flight"`
+	Stops      []string `slot:"stops"`
+}
+
+type flightResult struct {
+	Price    float64 `slot:"price"`
+	Currency *string `slot:"currency"`
+	Note     string  `slot:"note,omitempty"`
+	Internal string  `slot:"-"`
+}
+
+func searchFlights(received *flightSearch) Action {
+	return SlotAction("search_flights", func(_ context.Context, slots flightSearch, _ *rasa.Tracker,
+		_ responses.ResponseDispatcher) (flightResult, error) {
+		*received = slots
+		return flightResult{Price: 99.5 * float64(slots.Passengers), Internal: "x"}, nil
+	})
+}
+
+func executeSlotAction(action Action, slots map[string]interface{}, domain rasa.Domain) (
+	map[string]interface{}, error) {
+	actionRequest := request.CustomActionRequest{ActionToRun: action.Name(),
+		Tracker: rasa.Tracker{Slots: slots}, Domain: domain}
+
+	return ExecuteAction(&actionRequest, []Action{action})
+}
+
+func TestSlotActionBindsSlots(t *testing.T) {
+	var received flightSearch
+	slots := map[string]interface{}{"departure_city": "Berlin", "passengers": 2.0, "return_flight": true,
+		"stops": []interface{}{"Paris"}}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{"departure_city": {Type: "text"}, "passengers": {Type: "float"},
+		"return_flight": {Type: "bool"}, "stops": {Type: "list"}}}
+
+	response, err := executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.Nil(t, err)
+
+	returnFlight := true
+	assert.Equal(t, flightSearch{From: "Berlin", Passengers: 2, Return: &returnFlight, Stops: []string{"Paris"}},
+		received)
+	assert.Equal(t, events.WithTypeKeys(&events.SlotSet{Name: "price", Value: 199.0},
+		&events.SlotSet{Name: "currency", Value: nil}), response["events"])
+}
+
+func TestSlotActionWithMissingSlots(t *testing.T) {
+	var received flightSearch
+
+	_, err := executeSlotAction(searchFlights(&received), map[string]interface{}{"passengers": nil}, rasa.Domain{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, flightSearch{}, received)
+}
+
+func TestSlotActionWithInvalidSlotValue(t *testing.T) {
+	var received flightSearch
+
+	_, err := executeSlotAction(searchFlights(&received), map[string]interface{}{"passengers": "many"},
+		rasa.Domain{})
+
+	assert.IsType(t, &BadInputError{}, err)
+}
+
+func TestSlotActionWithMismatchingSlotType(t *testing.T) {
+	var received flightSearch
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{"passengers": {Type: "text"}}}
+
+	_, err := executeSlotAction(searchFlights(&received), map[string]interface{}{}, domain)
+
+	assert.IsType(t, &InternalError{}, err)
+}
+
+func TestSlotActionWithSlotTypesAsClassPaths(t *testing.T) {
+	var received flightSearch
+	slots := map[string]interface{}{"departure_city": "Berlin", "passengers": 2.0}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{"departure_city": {Type: "rasa.core.slots.TextSlot"},
+		"passengers": {Type: "rasa.shared.core.slots.FloatSlot"}}}
+
+	_, err := executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.Nil(t, err)
+	assert.Equal(t, flightSearch{From: "Berlin", Passengers: 2}, received)
+
+	domain = rasa.Domain{Slots: map[string]rasa.Slot{"passengers": {Type: "rasa.core.slots.TextSlot"}}}
+	_, err = executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.IsType(t, &InternalError{}, err)
+
+	domain = rasa.Domain{Slots: map[string]rasa.Slot{"passengers": {Type: "rasa.shared.core.slots.TextSlot"}}}
+	_, err = executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.IsType(t, &InternalError{}, err)
+}
+
+func TestSlotActionWithCategoricalSlots(t *testing.T) {
+	var received flightSearch
+	slots := map[string]interface{}{"departure_city": "Berlin", "passengers": 2.0, "return_flight": true}
+	domain := rasa.Domain{Slots: map[string]rasa.Slot{
+		"departure_city": {Type: "rasa.shared.core.slots.CategoricalSlot"},
+		"passengers":     {Type: "categorical", Values: []interface{}{1, 2}},
+		"return_flight":  {Type: "categorical", Values: []interface{}{true, false}},
+	}}
+
+	_, err := executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, received.Passengers)
+
+	domain = rasa.Domain{Slots: map[string]rasa.Slot{"stops": {Type: "categorical"}}}
+	_, err = executeSlotAction(searchFlights(&received), slots, domain)
+
+	assert.IsType(t, &InternalError{}, err)
+}
+
+func TestSlotActionReturnsError(t *testing.T) {
+	cause := errors.New("backend unavailable")
+	action := SlotAction("failing", func(_ context.Context, _ struct{}, _ *rasa.Tracker,
+		_ responses.ResponseDispatcher) (struct{}, error) {
+		return struct{}{}, cause
+	})
+
+	_, err := executeSlotAction(action, nil, rasa.Domain{})
+
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestSlotActionRequiresStructs(t *testing.T) {
+	assert.Panics(t, func() {
+		SlotAction("invalid", func(_ context.Context, _ string, _ *rasa.Tracker,
+			_ responses.ResponseDispatcher) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	})
+}
+
+func TestSlotActionMetadata(t *testing.T) {
+	var received flightSearch
+
+	metadata := MetadataOf(searchFlights(&received))
+
+	assert.Equal(t, Metadata{RequiredSlots: []string{"departure_city", "passengers", "return_flight", "stops"}},
+		metadata)
+}
+
+func TestMetadataOfActionWithoutMetadata(t *testing.T) {
+	assert.Equal(t, Metadata{}, MetadataOf(&SimpleTestAction{}))
+}
@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+)
+
+const (
+	formValidationPrefix = "validate_"
+	responseActionPrefix = "utter_"
+)
+
+// DomainMismatchError describes the differences between the served actions and the actions, forms and slots which
+// are declared in the domain.
+type DomainMismatchError struct {
+	// Unregistered are the actions which are declared in the domain but not served.
+	Unregistered []string
+	// Undeclared are the served actions which are not declared in the domain.
+	Undeclared []string
+	// MissingForms are the form validation actions whose form is not declared in the domain.
+	MissingForms []string
+	// UnknownSlots are the slots which are validated or extracted by form validation actions but not declared in the
+	// domain. The slots are grouped by action name.
+	UnknownSlots map[string][]string
+}
+
+func (e *DomainMismatchError) Error() string {
+	var problems []string
+
+	if len(e.Unregistered) > 0 {
+		problems = append(problems, fmt.Sprintf("actions declared in the domain but not served: %v", e.Unregistered))
+	}
+
+	if len(e.Undeclared) > 0 {
+		problems = append(problems, fmt.Sprintf("actions served but not declared in the domain: %v", e.Undeclared))
+	}
+
+	if len(e.MissingForms) > 0 {
+		problems = append(problems, fmt.Sprintf("form validation actions without form in the domain: %v",
+			e.MissingForms))
+	}
+
+	actionNames := make([]string, 0, len(e.UnknownSlots))
+	for actionName := range e.UnknownSlots {
+		actionNames = append(actionNames, actionName)
+	}
+
+	sort.Strings(actionNames)
+
+	for _, actionName := range actionNames {
+		problems = append(problems, fmt.Sprintf("action '%s' uses slots which are not declared in the domain: %v",
+			actionName, e.UnknownSlots[actionName]))
+	}
+
+	return fmt.Sprintf("actions don't match the domain: %s.", strings.Join(problems, "; "))
+}
+
+func (e *DomainMismatchError) isEmpty() bool {
+	return len(e.Unregistered) == 0 && len(e.Undeclared) == 0 && len(e.MissingForms) == 0 && len(e.UnknownSlots) == 0
+}
+
+// ValidateDomain checks that the served actions match the domain. A `DomainMismatchError` is returned if
+// - actions are declared in the domain but not served (responses starting with `utter_` are ignored),
+// - served actions are not declared in the domain,
+// - the form of a `validate_<form>` action is not declared in the domain,
+// - form validation actions validate or extract slots which are not declared in the domain.
+// Form validation actions are recognized by their name or by the `FormName` of their `Metadata`.
+func ValidateDomain(served []Action, domain *rasa.Domain) error {
+	mismatch := &DomainMismatchError{UnknownSlots: map[string][]string{}}
+	servedNames := make(map[string]bool, len(served))
+
+	for _, action := range served {
+		servedNames[action.Name()] = true
+	}
+
+	for _, declared := range domain.Actions {
+		if !servedNames[declared] && !strings.HasPrefix(declared, responseActionPrefix) {
+			mismatch.Unregistered = append(mismatch.Unregistered, declared)
+		}
+	}
+
+	declaredNames := make(map[string]bool, len(domain.Actions))
+	for _, declared := range domain.Actions {
+		declaredNames[declared] = true
+	}
+
+	for _, action := range served {
+		name := action.Name()
+		if !declaredNames[name] {
+			mismatch.Undeclared = append(mismatch.Undeclared, name)
+		}
+
+		metadata := MetadataOf(action)
+		if metadata.FormName == "" && !strings.HasPrefix(name, formValidationPrefix) {
+			continue
+		}
+
+		formName := metadata.FormName
+		if formName == "" {
+			formName = strings.TrimPrefix(name, formValidationPrefix)
+		}
+
+		if _, ok := domain.Forms[formName]; !ok {
+			mismatch.MissingForms = append(mismatch.MissingForms, name)
+		}
+
+		if unknownSlots := undeclaredSlots(metadata.RequiredSlots, domain); len(unknownSlots) > 0 {
+			mismatch.UnknownSlots[name] = unknownSlots
+		}
+	}
+
+	if mismatch.isEmpty() {
+		return nil
+	}
+
+	sort.Strings(mismatch.Unregistered)
+	sort.Strings(mismatch.Undeclared)
+	sort.Strings(mismatch.MissingForms)
+
+	return mismatch
+}
+
+func undeclaredSlots(slots []string, domain *rasa.Domain) []string {
+	var undeclared []string
+
+	for _, slot := range slots {
+		if _, ok := domain.Slots[slot]; !ok {
+			undeclared = append(undeclared, slot)
+		}
+	}
+
+	return undeclared
+}
@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+)
+
+type FormAction struct {
+	NamedAction
+	metadata Metadata
+}
+
+func (action *FormAction) Metadata() Metadata { return action.metadata }
+
+func TestValidateMatchingDomain(t *testing.T) {
+	domain := rasa.Domain{Actions: []string{"action_search", "validate_booking_form", "utter_greet"},
+		Forms: map[string]map[string]interface{}{"booking_form": {}},
+		Slots: map[string]rasa.Slot{"date": {Type: "text"}}}
+	served := []Action{&NamedAction{"action_search"}, &FormAction{NamedAction{"validate_booking_form"},
+		Metadata{FormName: "booking_form", RequiredSlots: []string{"date"}}}}
+
+	assert.Nil(t, ValidateDomain(served, &domain))
+}
+
+func TestValidateMismatchingDomain(t *testing.T) {
+	domain := rasa.Domain{Actions: []string{"action_serach", "validate_booking_form", "validate_other_form",
+		"action_weather"},
+		Forms: map[string]map[string]interface{}{"booking_form": {}},
+		Slots: map[string]rasa.Slot{"date": {Type: "text"}}}
+	served := []Action{
+		&NamedAction{"action_search"},
+		&FormAction{NamedAction{"validate_booking_form"},
+			Metadata{FormName: "booking_form", RequiredSlots: []string{"date", "guests", "time"}}},
+		&NamedAction{"validate_other_form"},
+	}
+
+	err := ValidateDomain(served, &domain)
+
+	assert.Equal(t, &DomainMismatchError{
+		Unregistered: []string{"action_serach", "action_weather"},
+		Undeclared:   []string{"action_search"},
+		MissingForms: []string{"validate_other_form"},
+		UnknownSlots: map[string][]string{"validate_booking_form": {"guests", "time"}},
+	}, err)
+	assert.Equal(t, "actions don't match the domain: "+
+		"actions declared in the domain but not served: [action_serach action_weather]; "+
+		"actions served but not declared in the domain: [action_search]; "+
+		"form validation actions without form in the domain: [validate_other_form]; "+
+		"action 'validate_booking_form' uses slots which are not declared in the domain: [guests time].",
+		err.Error())
+}
@@ -0,0 +1,41 @@
+// Package tracing contains helpers to instrument the SDK with OpenTelemetry (https://opentelemetry.io/).
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans which are created by the SDK.
+const InstrumentationName = "github.com/wochinge/go-rasa-sdk/v2"
+
+// Attribute keys which are used for the spans of the SDK.
+const (
+	ActionNameKey   = attribute.Key("rasa.action.name")
+	ActionFoundKey  = attribute.Key("rasa.action.found")
+	SenderIDKey     = attribute.Key("rasa.sender_id")
+	EventCountKey   = attribute.Key("rasa.events.count")
+	NewEventsKey    = attribute.Key("rasa.events.new_count")
+	InputChannelKey = attribute.Key("rasa.input_channel")
+)
+
+// Start starts a child span of the span in the given context. The tracer provider of the parent span is used so
+// that no spans are recorded if tracing is not enabled.
+func Start(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(InstrumentationName)
+
+	return tracer.Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// RecordError marks the span as failed in case `err` is not `nil`.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -3,6 +3,7 @@ package logging
 const (
 	ActionNameKey = "actionName"
 	ErrorKey      = "error"
+	StackTraceKey = "stackTrace"
 
 	ConversationIDKey = "conversation id"
 	EventKeys         = "events"
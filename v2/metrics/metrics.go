@@ -0,0 +1,288 @@
+// Package metrics implements a minimal metrics registry which exposes its metrics in the Prometheus text
+// exposition format (https://prometheus.io/docs/instrumenting/exposition_formats/).
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the default histogram buckets in seconds. They are the same as in the Prometheus client
+// libraries.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} // nolint:gochecknoglobals
+
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+// Registry holds metrics and renders them in the Prometheus text exposition format.
+type Registry struct {
+	mutex    sync.Mutex
+	families []*family
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a metric with all its label combinations.
+type family struct {
+	name       string
+	help       string
+	metricType metricType
+	labelNames []string
+	buckets    []float64
+
+	mutex  sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// bucketCounts is only used for histograms and contains non-cumulative counts per bucket.
+	bucketCounts []uint64
+	count        uint64
+}
+
+func (r *Registry) register(name, help string, metricType metricType, buckets []float64,
+	labelNames []string) *family {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.families {
+		if existing.name == name {
+			panic(fmt.Sprintf("metric '%s' is already registered", name))
+		}
+	}
+
+	registered := &family{name: name, help: help, metricType: metricType, buckets: buckets, labelNames: labelNames,
+		series: map[string]*series{}}
+	r.families = append(r.families, registered)
+
+	return registered
+}
+
+func (f *family) seriesFor(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric '%s' expects %d label values but got %d", f.name, len(f.labelNames),
+			len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	found, ok := f.series[key]
+	if !ok {
+		found = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.metricType == histogramType {
+			found.bucketCounts = make([]uint64, len(f.buckets))
+		}
+
+		f.series[key] = found
+	}
+
+	return found
+}
+
+// CounterVec is a counter which is partitioned by labels. Counters can only increase.
+type CounterVec struct{ family *family }
+
+// NewCounterVec registers a new counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{r.register(name, help, counterType, nil, labelNames)}
+}
+
+// Inc increments the counter for the given label values by 1.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the given non-negative value to the counter for the given label values.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("counter '%s' cannot decrease", c.family.name))
+	}
+
+	c.family.mutex.Lock()
+	defer c.family.mutex.Unlock()
+
+	c.family.seriesFor(labelValues).value += value
+}
+
+// GaugeVec is a gauge which is partitioned by labels. Gauges can go up and down.
+type GaugeVec struct{ family *family }
+
+// NewGaugeVec registers a new gauge with the given label names.
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{r.register(name, help, gaugeType, nil, labelNames)}
+}
+
+// Set sets the gauge for the given label values.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.family.mutex.Lock()
+	defer g.family.mutex.Unlock()
+
+	g.family.seriesFor(labelValues).value = value
+}
+
+// Add adds the given value (which can be negative) to the gauge for the given label values.
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	g.family.mutex.Lock()
+	defer g.family.mutex.Unlock()
+
+	g.family.seriesFor(labelValues).value += value
+}
+
+// HistogramVec counts observations in configurable buckets and is partitioned by labels.
+type HistogramVec struct{ family *family }
+
+// NewHistogramVec registers a new histogram with the given upper bucket bounds and label names.
+// `DefaultBuckets` are used in case `buckets` is empty.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &HistogramVec{r.register(name, help, histogramType, sorted, labelNames)}
+}
+
+// Observe adds an observation to the histogram for the given label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.family.mutex.Lock()
+	defer h.family.mutex.Unlock()
+
+	observed := h.family.seriesFor(labelValues)
+	observed.value += value
+	observed.count++
+
+	if index := sort.SearchFloat64s(h.family.buckets, value); index < len(h.family.buckets) {
+		observed.bucketCounts[index]++
+	}
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format to the writer.
+func (r *Registry) WriteTo(writer io.Writer) (int64, error) {
+	r.mutex.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mutex.Unlock()
+
+	var buffer bytes.Buffer
+
+	for _, metricFamily := range families {
+		metricFamily.writeTo(&buffer)
+	}
+
+	return buffer.WriteTo(writer)
+}
+
+// ServeHTTP exposes the metrics so that they can be scraped by Prometheus.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = r.WriteTo(w)
+}
+
+func (f *family) writeTo(buffer *bytes.Buffer) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	fmt.Fprintf(buffer, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(buffer, "# TYPE %s %s\n", f.name, f.metricType)
+
+	for _, key := range f.sortedSeriesKeys() {
+		current := f.series[key]
+
+		if f.metricType != histogramType {
+			writeSample(buffer, f.name, f.labelNames, current.labelValues, current.value)
+			continue
+		}
+
+		labelNames := append(append([]string(nil), f.labelNames...), "le")
+
+		var cumulative uint64
+
+		for index, upperBound := range f.buckets {
+			cumulative += current.bucketCounts[index]
+			writeSample(buffer, f.name+"_bucket", labelNames,
+				append(append([]string(nil), current.labelValues...), formatFloat(upperBound)), float64(cumulative))
+		}
+
+		writeSample(buffer, f.name+"_bucket", labelNames,
+			append(append([]string(nil), current.labelValues...), "+Inf"), float64(current.count))
+		writeSample(buffer, f.name+"_sum", f.labelNames, current.labelValues, current.value)
+		writeSample(buffer, f.name+"_count", f.labelNames, current.labelValues, float64(current.count))
+	}
+}
+
+func (f *family) sortedSeriesKeys() []string {
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func writeSample(buffer *bytes.Buffer, name string, labelNames, labelValues []string, value float64) {
+	buffer.WriteString(name)
+
+	if len(labelNames) > 0 {
+		buffer.WriteByte('{')
+
+		for index, labelName := range labelNames {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+
+			fmt.Fprintf(buffer, `%s="%s"`, labelName, escapeLabelValue(labelValues[index]))
+		}
+
+		buffer.WriteByte('}')
+	}
+
+	buffer.WriteByte(' ')
+	buffer.WriteString(formatFloat(value))
+	buffer.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
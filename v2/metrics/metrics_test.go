@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, registry *Registry) string {
+	var buffer bytes.Buffer
+
+	if _, err := registry.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.String()
+}
+
+func TestCounter(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("executions_total", "Number of executions.", "action")
+
+	counter.Inc("b")
+	counter.Inc("a")
+	counter.Add(2, "a")
+
+	expected := "# HELP executions_total Number of executions.\n" +
+		"# TYPE executions_total counter\n" +
+		"executions_total{action=\"a\"} 3\n" +
+		"executions_total{action=\"b\"} 1\n"
+	assert.Equal(t, expected, render(t, registry))
+}
+
+func TestCounterCannotDecrease(t *testing.T) {
+	counter := NewRegistry().NewCounterVec("counter", "help")
+
+	assert.Panics(t, func() { counter.Add(-1) })
+}
+
+func TestWrongNumberOfLabels(t *testing.T) {
+	counter := NewRegistry().NewCounterVec("counter", "help", "one", "two")
+
+	assert.Panics(t, func() { counter.Inc("only one") })
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("counter", "help")
+
+	assert.Panics(t, func() { registry.NewGaugeVec("counter", "help") })
+}
+
+func TestGaugeWithoutLabels(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGaugeVec("queue_depth", "Queued requests.")
+
+	gauge.Add(3)
+	gauge.Add(-1)
+
+	expected := "# HELP queue_depth Queued requests.\n# TYPE queue_depth gauge\nqueue_depth 2\n"
+	assert.Equal(t, expected, render(t, registry))
+
+	gauge.Set(0.5)
+	assert.Contains(t, render(t, registry), "queue_depth 0.5\n")
+}
+
+func TestHistogram(t *testing.T) {
+	registry := NewRegistry()
+	histogram := registry.NewHistogramVec("duration_seconds", "Duration.", []float64{1, 0.1}, "action")
+
+	histogram.Observe(0.05, "a")
+	histogram.Observe(0.1, "a")
+	histogram.Observe(0.5, "a")
+	histogram.Observe(3, "a")
+
+	expected := "# HELP duration_seconds Duration.\n" +
+		"# TYPE duration_seconds histogram\n" +
+		"duration_seconds_bucket{action=\"a\",le=\"0.1\"} 2\n" +
+		"duration_seconds_bucket{action=\"a\",le=\"1\"} 3\n" +
+		"duration_seconds_bucket{action=\"a\",le=\"+Inf\"} 4\n" +
+		"duration_seconds_sum{action=\"a\"} 3.65\n" +
+		"duration_seconds_count{action=\"a\"} 4\n"
+	assert.Equal(t, expected, render(t, registry))
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewHistogramVec("duration_seconds", "Duration.", nil).Observe(1)
+
+	output := render(t, registry)
+
+	for _, bucket := range []string{"0.005", "0.25", "10", "+Inf"} {
+		assert.Contains(t, output, `duration_seconds_bucket{le="`+bucket+`"} `)
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("counter", "Help with \\ and \n.", "label").Inc("quote \" backslash \\ newline \n")
+
+	expected := "# HELP counter Help with \\\\ and \\n.\n" +
+		"# TYPE counter counter\n" +
+		"counter{label=\"quote \\\" backslash \\\\ newline \\n\"} 1\n"
+	assert.Equal(t, expected, render(t, registry))
+}
+
+func TestServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("counter", "help").Inc()
+
+	request, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	registry.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, ContentType, response.Header().Get("Content-Type"))
+	assert.Equal(t, "# HELP counter help\n# TYPE counter counter\ncounter 1\n", response.Body.String())
+}
@@ -2,6 +2,11 @@
 package rasa
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
 )
 
@@ -23,6 +28,133 @@ type Domain struct {
 	Config Config `json:"config"`
 	// SessionConfig which is specified in the `domain.yml`.
 	SessionConfig SessionConfig `json:"session_config"`
+
+	// requiredSlotOrder keeps the order of the required slots of Rasa Open Source 2 forms which is lost when they
+	// are parsed into maps.
+	requiredSlotOrder map[string][]string
+}
+
+// UnmarshalJSON parses the domain. Domains of Rasa Open Source 1 list the names of the forms and specify the
+// responses as `templates`. Forms are converted to forms without configuration in this case.
+func (domain *Domain) UnmarshalJSON(data []byte) error {
+	type plainDomain Domain
+
+	var parsed struct {
+		plainDomain
+		Forms     json.RawMessage       `json:"forms"`
+		Templates map[string][]Response `json:"templates"`
+	}
+
+	parsed.plainDomain = plainDomain(*domain)
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+
+	*domain = Domain(parsed.plainDomain)
+
+	if domain.Responses == nil {
+		domain.Responses = parsed.Templates
+	}
+
+	if parsed.Forms == nil || string(parsed.Forms) == "null" {
+		return nil
+	}
+
+	var formNames []string
+	if err := json.Unmarshal(parsed.Forms, &formNames); err == nil {
+		domain.Forms = make(map[string]map[string]interface{}, len(formNames))
+		for _, formName := range formNames {
+			domain.Forms[formName] = map[string]interface{}{}
+		}
+
+		return nil
+	}
+
+	if err := json.Unmarshal(parsed.Forms, &domain.Forms); err != nil {
+		return err
+	}
+
+	domain.requiredSlotOrder = requiredSlotOrder(parsed.Forms)
+
+	return nil
+}
+
+// requiredSlotOrder returns the names of the required slots of each form in the order of the JSON document in case
+// the slots are specified as mapping from slot names to slot mappings.
+func requiredSlotOrder(rawForms json.RawMessage) map[string][]string {
+	var forms map[string]json.RawMessage
+	if err := json.Unmarshal(rawForms, &forms); err != nil {
+		return nil
+	}
+
+	order := make(map[string][]string, len(forms))
+
+	for formName, rawForm := range forms {
+		var form map[string]json.RawMessage
+		if err := json.Unmarshal(rawForm, &form); err != nil {
+			continue
+		}
+
+		if requiredSlots, ok := form["required_slots"]; ok {
+			if slotNames := objectKeys(requiredSlots); slotNames != nil {
+				order[formName] = slotNames
+			}
+
+			continue
+		}
+
+		// Rasa Open Source 2.0 specifies the slot mappings directly as part of the form.
+		for _, key := range objectKeys(rawForm) {
+			if key != "ignored_intents" {
+				order[formName] = append(order[formName], key)
+			}
+		}
+	}
+
+	return order
+}
+
+// objectKeys returns the keys of a JSON object in the order in which they appear. It returns `nil` if the JSON
+// value is not an object.
+func objectKeys(raw json.RawMessage) []string {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	keys := []string{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		keys = append(keys, token.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+	}
+
+	return keys
+}
+
+// ReadDomainFile reads a domain in the JSON format which Rasa Open Source sends to the action server, e.g. as
+// returned by the `GET /domain` endpoint of Rasa Open Source.
+func ReadDomainFile(path string) (*Domain, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var domain Domain
+	if err := json.Unmarshal(content, &domain); err != nil {
+		return nil, fmt.Errorf("parsing domain '%s' failed: %w", path, err)
+	}
+
+	return &domain, nil
 }
 
 // DomainIntent specifies an intent description with the domain.
@@ -34,8 +166,13 @@ type Slot struct {
 	Type string `json:"type"`
 	// InitialValue of the slot.
 	InitialValue interface{} `json:"initial_value"`
-	// AutoFill the slot when an entity with the same name was extracted.
+	// AutoFill the slot when an entity with the same name was extracted (Rasa Open Source 2).
 	AutoFill bool `json:"auto_fill"`
+	// Values which a categorical slot can take. Values can be strings, numbers or booleans, e.g. `- 1` in the
+	// `domain.yml`.
+	Values []interface{} `json:"values"`
+	// Mappings specify how the slot is filled (Rasa Open Source 3 and later).
+	Mappings []SlotMapping `json:"mappings"`
 }
 
 // Response represents a bot response in the `domain.yml`.
@@ -46,6 +183,10 @@ type Response struct {
 	Channel string `json:"channel"`
 	// Buttons which are part of the response.
 	Buttons []responses.Button `json:"buttons"`
+	// ImageURL is the url of an image which is part of the response.
+	ImageURL string `json:"image"`
+	// Custom is a custom payload which is part of the response.
+	Custom interface{} `json:"custom"`
 }
 
 // Config to specify if entities should be stored as slots.
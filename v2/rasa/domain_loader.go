@@ -0,0 +1,333 @@
+package rasa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDomain loads the domain from a `domain.yml` file, from a JSON file (see `ReadDomainFile`) or from a directory
+// which contains the domain split into several YAML files.
+// Split domain files are merged in lexical order of their paths. Intents, entities and actions are combined. For
+// slots, responses and forms as well as for the configuration, the first file which specifies them takes precedence.
+func LoadDomain(path string) (*Domain, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return loadDomainDirectory(path)
+	}
+
+	if filepath.Ext(path) == ".json" {
+		return ReadDomainFile(path)
+	}
+
+	raw, slotOrder, err := readDomainYAML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFromRaw(raw, slotOrder)
+}
+
+// ParseDomain parses the content of a `domain.yml` file.
+func ParseDomain(content []byte) (*Domain, error) {
+	raw, slotOrder, err := parseDomainYAML(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainFromRaw(raw, slotOrder)
+}
+
+func loadDomainDirectory(directory string) (*Domain, error) {
+	var paths []string
+
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if extension := filepath.Ext(path); !entry.IsDir() && (extension == ".yml" || extension == ".yaml") {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	merged := map[string]interface{}{}
+	mergedSlotOrder := map[string][]string{}
+
+	for _, path := range paths {
+		raw, slotOrder, err := readDomainYAML(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// The forms of the first file take precedence and so does the order of their slots.
+		if forms, ok := merged["forms"].(map[string]interface{}); ok {
+			for formName := range forms {
+				delete(slotOrder, formName)
+			}
+		}
+
+		for formName, slotNames := range slotOrder {
+			mergedSlotOrder[formName] = slotNames
+		}
+
+		mergeDomains(merged, raw)
+	}
+
+	return domainFromRaw(merged, mergedSlotOrder)
+}
+
+func readDomainYAML(path string) (map[string]interface{}, map[string][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, slotOrder, err := parseDomainYAML(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing domain '%s' failed: %w", path, err)
+	}
+
+	return raw, slotOrder, nil
+}
+
+// parseDomainYAML parses a `domain.yml` file into a raw domain. The order of the required slots of forms is returned
+// separately as it's lost when the forms are parsed into maps.
+func parseDomainYAML(content []byte) (map[string]interface{}, map[string][]string, error) {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	raw["intents"] = normalizedIntents(raw["intents"])
+	raw["entities"] = normalizedEntities(raw["entities"])
+
+	var document struct {
+		Forms map[string]yaml.Node `yaml:"forms"`
+	}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		// Rasa Open Source 1 lists the names of the forms.
+		return raw, nil, nil
+	}
+
+	return raw, requiredSlotOrderOfYAML(document.Forms), nil
+}
+
+// requiredSlotOrderOfYAML returns the names of the required slots of each form in the order of the YAML document in
+// case the slots are specified as mapping from slot names to slot mappings.
+func requiredSlotOrderOfYAML(forms map[string]yaml.Node) map[string][]string {
+	order := make(map[string][]string, len(forms))
+
+	for formName := range forms {
+		form := forms[formName]
+		if form.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if requiredSlots := mappingValue(&form, "required_slots"); requiredSlots != nil {
+			if slotNames := mappingKeys(requiredSlots); slotNames != nil {
+				order[formName] = slotNames
+			}
+
+			continue
+		}
+
+		// Rasa Open Source 2.0 specifies the slot mappings directly as part of the form.
+		for _, key := range mappingKeys(&form) {
+			if key != "ignored_intents" {
+				order[formName] = append(order[formName], key)
+			}
+		}
+	}
+
+	return order
+}
+
+// mappingKeys returns the keys of a YAML mapping in the order of the document or `nil` if the node isn't a mapping.
+func mappingKeys(node *yaml.Node) []string {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return keys
+}
+
+// mappingValue returns the value of the given key of a YAML mapping or `nil` if the key doesn't exist.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// normalizedIntents turns intents into the format which Rasa Open Source sends to the action server, i.e. a list
+// of mappings from the intent name to its properties.
+func normalizedIntents(intents interface{}) []interface{} {
+	items, _ := intents.([]interface{})
+	normalized := make([]interface{}, 0, len(items))
+
+	for _, intent := range items {
+		switch typed := intent.(type) {
+		case string:
+			normalized = append(normalized, map[string]interface{}{typed: map[string]interface{}{}})
+		default:
+			normalized = append(normalized, typed)
+		}
+	}
+
+	return normalized
+}
+
+// normalizedEntities returns the names of the entities. Entities can be specified together with their roles and
+// groups.
+func normalizedEntities(entities interface{}) []interface{} {
+	items, _ := entities.([]interface{})
+	normalized := make([]interface{}, 0, len(items))
+
+	for _, entity := range items {
+		normalized = append(normalized, itemName(entity))
+	}
+
+	return normalized
+}
+
+// itemName returns the name of a list item which is either a plain string or a mapping from the name to properties.
+func itemName(item interface{}) string {
+	if properties, ok := item.(map[string]interface{}); ok && len(properties) == 1 {
+		for name := range properties {
+			return name
+		}
+	}
+
+	return fmt.Sprint(item)
+}
+
+// mergeDomains merges the raw domain `other` into `merged`.
+func mergeDomains(merged, other map[string]interface{}) {
+	// Keys of the domain whose values are lists or mappings. Their items are merged.
+	domainListKeys := []string{"intents", "entities", "actions"}
+	domainMapKeys := []string{"slots", "responses", "forms"}
+
+	for _, key := range domainListKeys {
+		existing, _ := merged[key].([]interface{})
+		names := make(map[string]bool, len(existing))
+
+		for _, item := range existing {
+			names[itemName(item)] = true
+		}
+
+		items, _ := other[key].([]interface{})
+		for _, item := range items {
+			if !names[itemName(item)] {
+				existing = append(existing, item)
+				names[itemName(item)] = true
+			}
+		}
+
+		merged[key] = existing
+	}
+
+	for _, key := range domainMapKeys {
+		existing, _ := merged[key].(map[string]interface{})
+		if existing == nil {
+			existing = map[string]interface{}{}
+		}
+
+		entries, _ := other[key].(map[string]interface{})
+		for name, value := range entries {
+			if _, ok := existing[name]; !ok {
+				existing[name] = value
+			}
+		}
+
+		merged[key] = existing
+	}
+
+	for key, value := range other {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+		}
+	}
+}
+
+// domainFromRaw converts a raw domain into a `Domain` using the same field mapping as for domains which are sent by
+// Rasa Open Source. `slotOrder` contains the order of the required slots of forms which is lost in the raw domain.
+func domainFromRaw(raw map[string]interface{}, slotOrder map[string][]string) (*Domain, error) {
+	applyDefaults(raw)
+
+	serialized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var domain Domain
+	if err := json.Unmarshal(serialized, &domain); err != nil {
+		return nil, err
+	}
+
+	for formName, slotNames := range slotOrder {
+		if _, ok := domain.requiredSlotOrder[formName]; ok {
+			domain.requiredSlotOrder[formName] = slotNames
+		}
+	}
+
+	return &domain, nil
+}
+
+// applyDefaults sets the values which Rasa Open Source uses for keys which are missing in the `domain.yml`. Rasa Open
+// Source sends domains to the action server with these defaults already applied.
+func applyDefaults(raw map[string]interface{}) {
+	slots, _ := raw["slots"].(map[string]interface{})
+	for _, slot := range slots {
+		if properties, ok := slot.(map[string]interface{}); ok {
+			setDefault(properties, "auto_fill", true)
+		}
+	}
+
+	sessionConfig, ok := raw["session_config"].(map[string]interface{})
+	if !ok {
+		sessionConfig = map[string]interface{}{}
+		raw["session_config"] = sessionConfig
+	}
+
+	setDefault(sessionConfig, "session_expiration_time", 60)
+	setDefault(sessionConfig, "carry_over_slots_to_new_session", true)
+
+	config, ok := raw["config"].(map[string]interface{})
+	if !ok {
+		config = map[string]interface{}{}
+		raw["config"] = config
+	}
+
+	setDefault(config, "store_entities_as_slots", true)
+}
+
+func setDefault(properties map[string]interface{}, key string, value interface{}) {
+	if current, ok := properties[key]; !ok || current == nil {
+		properties[key] = value
+	}
+}
@@ -0,0 +1,135 @@
+package rasa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+)
+
+func TestReadDomainFile(t *testing.T) {
+	domain, err := ReadDomainFile("testdata/domain.json")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"action_search_flights", "validate_booking_form", "utter_greet"}, domain.Actions)
+	assert.Contains(t, domain.Forms, "booking_form")
+	assert.Equal(t, Slot{Type: "text", AutoFill: true}, domain.Slots["departure_city"])
+	assert.Equal(t, []Response{{Text: "Hello!"}}, domain.Responses["utter_greet"])
+}
+
+func TestReadMissingDomainFile(t *testing.T) {
+	_, err := ReadDomainFile("testdata/missing.json")
+
+	assert.NotNil(t, err)
+}
+
+func TestReadInvalidDomainFile(t *testing.T) {
+	_, err := ReadDomainFile("tracker.go")
+
+	assert.Contains(t, err.Error(), "parsing domain 'tracker.go' failed")
+}
+
+func TestLoadDomainFromYAML(t *testing.T) {
+	domain, err := LoadDomain("testdata/domain.yml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []DomainIntent{
+		{"greet": map[string]interface{}{}},
+		{"inform": map[string]interface{}{"use_entities": []interface{}{"city"}}},
+		{"chitchat": map[string]interface{}{"ignore_entities": true}},
+	}, domain.Intents)
+	assert.Equal(t, []string{"city", "name"}, domain.Entities)
+	assert.Equal(t, map[string]Slot{
+		"city":    {Type: "text"},
+		"guests":  {Type: "float", InitialValue: 1.0, AutoFill: true},
+		"seating": {Type: "categorical", Values: []interface{}{"inside", "outside"}, AutoFill: true},
+	}, domain.Slots)
+	assert.Equal(t, []Response{{Text: "Hey!"}, {Text: "Hey, welcome!", Channel: "slack"}},
+		domain.Responses["utter_greet"])
+	assert.Equal(t, []Response{
+		{Text: "Where to?", Buttons: []responses.Button{{Title: "Berlin", PayLoad: `/inform{"city":"Berlin"}`}}},
+		{ImageURL: "https://example.com/map.png", Custom: map[string]interface{}{"type": "map"}},
+	}, domain.Responses["utter_ask_city"])
+	assert.Equal(t, []string{"action_search_flights", "validate_booking_form"}, domain.Actions)
+	assert.Contains(t, domain.Forms, "booking_form")
+	assert.Equal(t, SessionConfig{SessionExpirationTime: 60, CarryOverSlotsToNewSession: true}, domain.SessionConfig)
+	assert.Equal(t, Config{StoreEntitiesAsSlots: false}, domain.Config)
+}
+
+func TestLoadDomainFromDirectory(t *testing.T) {
+	domain, err := LoadDomain("testdata/split_domain")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []DomainIntent{
+		{"greet": map[string]interface{}{}}, {"inform": map[string]interface{}{}}, {"deny": map[string]interface{}{}},
+	}, domain.Intents)
+	assert.Equal(t, map[string]Slot{"city": {Type: "text", AutoFill: true}, "guests": {Type: "float", AutoFill: true}},
+		domain.Slots)
+	assert.Equal(t, []Response{{Text: "Hey!"}}, domain.Responses["utter_greet"])
+	assert.Contains(t, domain.Responses, "utter_ask_guests")
+	assert.Equal(t, []string{"action_search_flights", "validate_booking_form"}, domain.Actions)
+	assert.Equal(t, []string{"guests", "city"}, domain.RequiredSlots("booking_form"))
+	assert.Equal(t, SessionConfig{SessionExpirationTime: 60, CarryOverSlotsToNewSession: true}, domain.SessionConfig)
+	assert.Equal(t, Config{StoreEntitiesAsSlots: true}, domain.Config)
+}
+
+func TestLoadDomainFromJSON(t *testing.T) {
+	domain, err := LoadDomain("testdata/domain.json")
+
+	assert.Nil(t, err)
+	assert.Contains(t, domain.Forms, "booking_form")
+}
+
+func TestLoadMissingDomain(t *testing.T) {
+	_, err := LoadDomain("testdata/missing.yml")
+
+	assert.NotNil(t, err)
+}
+
+func TestParseInvalidDomain(t *testing.T) {
+	_, err := ParseDomain([]byte("intents: [greet"))
+
+	assert.NotNil(t, err)
+}
+
+func TestParseDomainAppliesRasaDefaults(t *testing.T) {
+	domain, err := ParseDomain([]byte(`
+slots:
+  city:
+    type: text
+session_config:
+  session_expiration_time: 0
+`))
+
+	assert.Nil(t, err)
+	assert.True(t, domain.Slots["city"].AutoFill)
+	assert.Equal(t, SessionConfig{SessionExpirationTime: 0, CarryOverSlotsToNewSession: true}, domain.SessionConfig)
+	assert.True(t, domain.Config.StoreEntitiesAsSlots)
+}
+
+func TestParseDomainWithNonStringCategories(t *testing.T) {
+	domain, err := ParseDomain([]byte(`
+slots:
+  floor:
+    type: categorical
+    values: [1, 2, true, roof]
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0, true, "roof"}, domain.Slots["floor"].Values)
+
+	tracker := Tracker{Slots: map[string]interface{}{"floor": 2.0}}
+	assert.Nil(t, tracker.CheckSlot("floor", domain))
+
+	tracker.Slots["floor"] = "True"
+	assert.Nil(t, tracker.CheckSlot("floor", domain))
+
+	tracker.Slots["floor"] = 3.0
+	assert.IsType(t, &SlotTypeError{}, tracker.CheckSlot("floor", domain))
+}
+
+func TestParseDomainWithInvalidValues(t *testing.T) {
+	_, err := ParseDomain([]byte("actions: {action: 1}"))
+
+	assert.NotNil(t, err)
+}
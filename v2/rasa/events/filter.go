@@ -0,0 +1,36 @@
+package events
+
+// Filter returns the events of type `T` in the order in which they happened, e.g.
+// `events.Filter[*events.User](tracker.Events)` returns all user messages.
+func Filter[T Event](all []Event) []T {
+	var filtered []T
+
+	for _, event := range all {
+		if typed, ok := event.(T); ok {
+			filtered = append(filtered, typed)
+		}
+	}
+
+	return filtered
+}
+
+// Latest returns the latest event of type `T`. `found` is `false` if there is no event of this type.
+func Latest[T Event](all []Event) (latest T, found bool) {
+	for i := len(all) - 1; i >= 0; i-- {
+		if typed, ok := all[i].(T); ok {
+			return typed, true
+		}
+	}
+
+	return latest, false
+}
+
+// ForEach calls `visit` for every event of type `T` in the order in which the events happened. The iteration stops
+// when `visit` returns `false`.
+func ForEach[T Event](all []Event, visit func(T) bool) {
+	for _, event := range all {
+		if typed, ok := event.(T); ok && !visit(typed) {
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var history = []Event{
+	&User{Text: "hi"},
+	&Action{Name: "utter_greet"},
+	&Bot{Text: "Hey!"},
+	&User{Text: "bye"},
+	&Action{Name: "utter_bye"},
+}
+
+func TestFilter(t *testing.T) {
+	assert.Equal(t, []*User{{Text: "hi"}, {Text: "bye"}}, Filter[*User](history))
+	assert.Empty(t, Filter[*Restarted](history))
+}
+
+func TestLatest(t *testing.T) {
+	latest, found := Latest[*Action](history)
+
+	assert.True(t, found)
+	assert.Equal(t, "utter_bye", latest.Name)
+
+	_, found = Latest[*SlotSet](history)
+	assert.False(t, found)
+}
+
+func TestForEach(t *testing.T) {
+	var visited []string
+
+	ForEach(history, func(message *User) bool {
+		visited = append(visited, message.Text)
+		return false
+	})
+
+	assert.Equal(t, []string{"hi"}, visited)
+}
@@ -0,0 +1,47 @@
+package events
+
+// AsLoopEvents returns the events in the form which Rasa Open Source 2 and later expect: `Form` events are replaced
+// by `ActiveLoop` events and `FormValidation` events by `LoopInterrupted` events. Other events are kept as they are.
+func AsLoopEvents(all []Event) []Event {
+	converted := make([]Event, 0, len(all))
+
+	for _, event := range all {
+		switch typed := event.(type) {
+		case *Form:
+			converted = append(converted, &ActiveLoop{Base: withoutType(typed.Base), Name: typed.Name})
+		case *FormValidation:
+			converted = append(converted,
+				&LoopInterrupted{Base: withoutType(typed.Base), IsInterrupted: !typed.Validate})
+		default:
+			converted = append(converted, event)
+		}
+	}
+
+	return converted
+}
+
+// AsFormEvents returns the events in the form which Rasa Open Source 1 expects: `ActiveLoop` events are replaced
+// by `Form` events and `LoopInterrupted` events by `FormValidation` events. Other events are kept as they are.
+func AsFormEvents(all []Event) []Event {
+	converted := make([]Event, 0, len(all))
+
+	for _, event := range all {
+		switch typed := event.(type) {
+		case *ActiveLoop:
+			converted = append(converted, &Form{Base: withoutType(typed.Base), Name: typed.Name})
+		case *LoopInterrupted:
+			converted = append(converted,
+				&FormValidation{Base: withoutType(typed.Base), Validate: !typed.IsInterrupted})
+		default:
+			converted = append(converted, event)
+		}
+	}
+
+	return converted
+}
+
+// withoutType returns a copy of the base without type key so that the type key of the converted event is used.
+func withoutType(base Base) Base {
+	base.Type = ""
+	return base
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsLoopEvents(t *testing.T) {
+	converted := AsLoopEvents([]Event{
+		&Form{Base: Base{Type: form, Timestamp: 1}, Name: "restaurant_form"},
+		&FormValidation{Validate: false},
+		&SlotSet{Name: "cuisine", Value: "italian"},
+		&Form{},
+	})
+
+	assert.Equal(t, []Event{
+		&ActiveLoop{Base: Base{Timestamp: 1}, Name: "restaurant_form"},
+		&LoopInterrupted{IsInterrupted: true},
+		&SlotSet{Name: "cuisine", Value: "italian"},
+		&ActiveLoop{},
+	}, converted)
+}
+
+func TestAsFormEvents(t *testing.T) {
+	converted := AsFormEvents([]Event{
+		&ActiveLoop{Name: "restaurant_form"},
+		&LoopInterrupted{Base: Base{Type: loopInterrupted}, IsInterrupted: false},
+		&Restarted{},
+	})
+
+	assert.Equal(t, []Event{
+		&Form{Name: "restaurant_form"},
+		&FormValidation{Validate: true},
+		&Restarted{},
+	}, converted)
+}
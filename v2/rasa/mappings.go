@@ -0,0 +1,156 @@
+package rasa
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Slot mapping types (https://rasa.com/docs/rasa/domain/#slot-mappings).
+const (
+	MappingFromEntity        = "from_entity"
+	MappingFromText          = "from_text"
+	MappingFromIntent        = "from_intent"
+	MappingFromTriggerIntent = "from_trigger_intent"
+	MappingCustom            = "custom"
+)
+
+// RequestedSlot is the slot which stores the name of the slot which is currently requested by a form.
+const RequestedSlot = "requested_slot"
+
+// SlotMapping specifies how a slot is filled (Rasa Open Source 3 and later).
+type SlotMapping struct {
+	// Type of the mapping, e.g. `from_entity`.
+	Type string `json:"type"`
+	// Entity whose value fills the slot for `from_entity` mappings.
+	Entity string `json:"entity,omitempty"`
+	// Role which the entity must have.
+	Role string `json:"role,omitempty"`
+	// Group which the entity must belong to.
+	Group string `json:"group,omitempty"`
+	// Value which fills the slot for `from_intent` and `from_trigger_intent` mappings.
+	Value interface{} `json:"value,omitempty"`
+	// Intent restricts the mapping to messages with one of these intents.
+	Intent IntentNames `json:"intent,omitempty"`
+	// NotIntent excludes messages with one of these intents from the mapping.
+	NotIntent IntentNames `json:"not_intent,omitempty"`
+	// Action which fills the slot for `custom` mappings.
+	Action string `json:"action,omitempty"`
+	// Conditions restrict the mapping to certain forms and requested slots.
+	Conditions []MappingCondition `json:"conditions,omitempty"`
+}
+
+// MappingCondition restricts a slot mapping to an active loop and optionally to a requested slot.
+type MappingCondition struct {
+	// ActiveLoop which has to be active. An empty name means that no loop must be active.
+	ActiveLoop string `json:"active_loop"`
+	// RequestedSlot which has to be requested by the active loop. Any slot can be requested if it's empty.
+	RequestedSlot string `json:"requested_slot,omitempty"`
+}
+
+// IntentNames are the intents of a slot mapping. They can be specified as single intent or as list of intents.
+type IntentNames []string
+
+// UnmarshalJSON parses a single intent name or a list of intent names.
+func (names *IntentNames) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*names = IntentNames{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*names = list
+
+	return nil
+}
+
+// ConditionsMet returns `true` if the mapping applies to the current state of the conversation. Mappings without
+// conditions always apply.
+func (mapping SlotMapping) ConditionsMet(tracker *Tracker) bool {
+	if len(mapping.Conditions) == 0 {
+		return true
+	}
+
+	requested, _ := tracker.Slots[RequestedSlot].(string)
+
+	for _, condition := range mapping.Conditions {
+		if condition.ActiveLoop != tracker.ActiveLoop.Name {
+			continue
+		}
+
+		if condition.RequestedSlot == "" || condition.RequestedSlot == requested {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasMappingOfType returns `true` if the slot has a mapping of the given type.
+func (slot Slot) HasMappingOfType(mappingType string) bool {
+	for _, mapping := range slot.Mappings {
+		if mapping.Type == mappingType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequiredSlots returns the slots which are required by the form in the order in which they are specified in the
+// domain. Rasa Open Source 3 specifies the required slots as list. Rasa Open Source 2 specifies them as mapping from
+// slot names to slot mappings. Their order is kept when the domain is parsed from JSON (e.g. when it's sent by Rasa
+// Open Source) or loaded with `LoadDomain` / `ParseDomain`. Go maps have no order, so the slot names of such forms are
+// returned in lexical order for domains which are built in Go code. Use a list of slot names to specify the order.
+func (domain *Domain) RequiredSlots(formName string) []string {
+	form, ok := domain.Forms[formName]
+	if !ok {
+		return nil
+	}
+
+	if order, ok := domain.requiredSlotOrder[formName]; ok {
+		return append([]string(nil), order...)
+	}
+
+	requiredSlots, ok := form["required_slots"]
+	if !ok {
+		// Rasa Open Source 2.0 specifies the slot mappings directly as part of the form.
+		legacyForm := make(map[string]interface{}, len(form))
+		for key, value := range form {
+			if key != "ignored_intents" {
+				legacyForm[key] = value
+			}
+		}
+
+		requiredSlots = legacyForm
+	}
+
+	switch requiredSlots := requiredSlots.(type) {
+	case []interface{}:
+		slotNames := make([]string, 0, len(requiredSlots))
+		for _, slotName := range requiredSlots {
+			if name, ok := slotName.(string); ok {
+				slotNames = append(slotNames, name)
+			}
+		}
+
+		return slotNames
+	case []string:
+		return requiredSlots
+	case map[string]interface{}:
+		slotNames := make([]string, 0, len(requiredSlots))
+		for name := range requiredSlots {
+			slotNames = append(slotNames, name)
+		}
+
+		sort.Strings(slotNames)
+
+		return slotNames
+	default:
+		return nil
+	}
+}
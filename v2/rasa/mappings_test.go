@@ -0,0 +1,100 @@
+package rasa
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRasa3Domain(t *testing.T) {
+	domain, err := LoadDomain("testdata/domain_v3.yml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []SlotMapping{{
+		Type: MappingFromEntity, Entity: "city", Role: "destination", Intent: IntentNames{"inform"},
+		Conditions: []MappingCondition{{ActiveLoop: "restaurant_form", RequestedSlot: "destination"}},
+	}}, domain.Slots["destination"].Mappings)
+	assert.Equal(t, []SlotMapping{{Type: MappingFromIntent, Intent: IntentNames{"affirm", "inform"},
+		NotIntent: IntentNames{"deny"}, Value: true}}, domain.Slots["outdoor_seating"].Mappings)
+	assert.True(t, domain.Slots["num_people"].HasMappingOfType(MappingCustom))
+	assert.False(t, domain.Slots["cuisine"].HasMappingOfType(MappingCustom))
+	assert.Equal(t, []string{"cuisine", "num_people", "outdoor_seating"}, domain.RequiredSlots("restaurant_form"))
+}
+
+func TestParseInvalidIntentNames(t *testing.T) {
+	var mapping SlotMapping
+
+	err := json.Unmarshal([]byte(`{"type": "from_intent", "intent": 5}`), &mapping)
+
+	assert.NotNil(t, err)
+}
+
+func TestRequiredSlotsOfRasa2Forms(t *testing.T) {
+	domain := Domain{Forms: map[string]map[string]interface{}{
+		"booking_form": {"required_slots": map[string]interface{}{"guests": nil, "city": nil}},
+		"legacy_form":  {"ignored_intents": []interface{}{"chitchat"}, "name": []interface{}{}},
+	}}
+
+	assert.Equal(t, []string{"city", "guests"}, domain.RequiredSlots("booking_form"))
+	assert.Equal(t, []string{"name"}, domain.RequiredSlots("legacy_form"))
+	assert.Nil(t, domain.RequiredSlots("unknown_form"))
+}
+
+func TestRequiredSlotsKeepOrderOfJSONDomain(t *testing.T) {
+	var domain Domain
+
+	err := json.Unmarshal([]byte(`{"forms": {
+		"booking_form": {"required_slots": {"guests": [], "city": [], "arrival": []}},
+		"legacy_form": {"name": [], "ignored_intents": [], "age": []},
+		"rasa_3_form": {"required_slots": ["seating", "cuisine"]}
+	}}`), &domain)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"guests", "city", "arrival"}, domain.RequiredSlots("booking_form"))
+	assert.Equal(t, []string{"name", "age"}, domain.RequiredSlots("legacy_form"))
+	assert.Equal(t, []string{"seating", "cuisine"}, domain.RequiredSlots("rasa_3_form"))
+}
+
+func TestRequiredSlotsKeepOrderOfYAMLDomain(t *testing.T) {
+	domain, err := ParseDomain([]byte(`
+forms:
+  booking_form:
+    required_slots:
+      guests: []
+      city: []
+      arrival: []
+  legacy_form:
+    name: []
+    ignored_intents: []
+    age: []
+  rasa_3_form:
+    required_slots: [b, a]
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"guests", "city", "arrival"}, domain.RequiredSlots("booking_form"))
+	assert.Equal(t, []string{"name", "age"}, domain.RequiredSlots("legacy_form"))
+	assert.Equal(t, []string{"b", "a"}, domain.RequiredSlots("rasa_3_form"))
+}
+
+func TestMappingConditions(t *testing.T) {
+	mapping := SlotMapping{Type: MappingFromText, Conditions: []MappingCondition{
+		{ActiveLoop: "booking_form", RequestedSlot: "city"},
+		{ActiveLoop: "restaurant_form"},
+	}}
+
+	tracker := Tracker{ActiveLoop: ActiveLoop{Name: "booking_form"},
+		Slots: map[string]interface{}{RequestedSlot: "city"}}
+	assert.True(t, mapping.ConditionsMet(&tracker))
+
+	tracker.Slots[RequestedSlot] = "guests"
+	assert.False(t, mapping.ConditionsMet(&tracker))
+
+	tracker.ActiveLoop.Name = "restaurant_form"
+	assert.True(t, mapping.ConditionsMet(&tracker))
+
+	tracker.ActiveLoop = ActiveLoop{}
+	assert.False(t, mapping.ConditionsMet(&tracker))
+	assert.True(t, SlotMapping{Type: MappingFromText}.ConditionsMet(&tracker))
+}
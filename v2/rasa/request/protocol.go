@@ -0,0 +1,56 @@
+package request
+
+import (
+	"encoding/json"
+)
+
+// Protocol describes the shape of the payloads which Rasa Open Source sends and expects.
+type Protocol int
+
+const (
+	// ProtocolActiveLoop is used by Rasa Open Source 2 and later. Forms are run by Rasa Open Source as `active_loop`
+	// and are (de-)activated with `ActiveLoop` and `LoopInterrupted` events.
+	ProtocolActiveLoop Protocol = iota
+	// ProtocolActiveForm is used by Rasa Open Source 1. Forms are run by the action server as `active_form` and are
+	// (de-)activated with `Form` and `FormValidation` events.
+	ProtocolActiveForm
+)
+
+func (protocol Protocol) String() string {
+	if protocol == ProtocolActiveForm {
+		return "active_form"
+	}
+
+	return "active_loop"
+}
+
+// UnmarshalJSON parses the request and detects its protocol. The active form of trackers sent by
+// Rasa Open Source 1 is provided as `ActiveLoop` of the tracker so that actions can handle requests of
+// both protocols in the same way.
+func (actionRequest *CustomActionRequest) UnmarshalJSON(data []byte) error {
+	type plainRequest CustomActionRequest
+	if err := json.Unmarshal(data, (*plainRequest)(actionRequest)); err != nil {
+		return err
+	}
+
+	var legacy struct {
+		Tracker struct {
+			ActiveForm json.RawMessage `json:"active_form"`
+		} `json:"tracker"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	actionRequest.Protocol = ProtocolActiveLoop
+
+	if legacy.Tracker.ActiveForm != nil || actionRequest.MajorVersion() == 1 {
+		actionRequest.Protocol = ProtocolActiveForm
+	}
+
+	if legacy.Tracker.ActiveForm != nil && string(legacy.Tracker.ActiveForm) != "null" {
+		return json.Unmarshal(legacy.Tracker.ActiveForm, &actionRequest.Tracker.ActiveLoop)
+	}
+
+	return nil
+}
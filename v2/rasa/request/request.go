@@ -3,9 +3,13 @@
 package request
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"strconv"
+	"strings"
 
+	"github.com/wochinge/go-rasa-sdk/v2/internal/tracing"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
 )
@@ -13,21 +17,36 @@ import (
 // Parsed parses the payload which is sent to us from Rasa Open Source upon a request to execute a custom action.
 // It returns the parsed payload or an error in case the json -> struct conversion failed.
 func Parsed(requestBody io.Reader) (CustomActionRequest, error) {
-	var parsedRequest CustomActionRequest
+	return ParsedContext(context.Background(), requestBody)
+}
+
+// ParsedContext is like `Parsed` but records tracing spans for the parsing in case the context contains a span.
+func ParsedContext(ctx context.Context, requestBody io.Reader) (parsedRequest CustomActionRequest, err error) {
+	ctx, span := tracing.Start(ctx, "parse request")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	parsedRequest.Tracker = *rasa.EmptyTracker()
 
 	decoder := json.NewDecoder(requestBody)
 
-	if err := decoder.Decode(&parsedRequest); err != nil {
+	if err = decoder.Decode(&parsedRequest); err != nil {
 		return parsedRequest, err
 	}
 
+	span.SetAttributes(tracing.ActionNameKey.String(parsedRequest.ActionToRun),
+		tracing.SenderIDKey.String(parsedRequest.Tracker.ConversationID),
+		tracing.InputChannelKey.String(parsedRequest.Tracker.LatestInputChannel),
+		tracing.EventCountKey.Int(len(parsedRequest.Tracker.RawEvents)))
+
 	if parsedRequest.Tracker.RawEvents == nil {
 		parsedRequest.Tracker.Events = []events.Event{}
 		return parsedRequest, nil
 	}
 
-	trackerEvents, err := events.Parsed(parsedRequest.Tracker.RawEvents)
+	trackerEvents, err := parsedEvents(ctx, parsedRequest.Tracker.RawEvents)
 
 	if err != nil {
 		return parsedRequest, err
@@ -38,6 +57,16 @@ func Parsed(requestBody io.Reader) (CustomActionRequest, error) {
 	return parsedRequest, err
 }
 
+func parsedEvents(ctx context.Context, rawEvents []json.RawMessage) (parsed []events.Event, err error) {
+	_, span := tracing.Start(ctx, "parse events", tracing.EventCountKey.Int(len(rawEvents)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	return events.Parsed(rawEvents)
+}
+
 // CustomActionRequest exposes the data which Rasa Open Source sends as part of the action execution request.
 type CustomActionRequest struct {
 	// ActionToRun is the action which Rasa Open Source wants to run.
@@ -46,4 +75,20 @@ type CustomActionRequest struct {
 	Tracker rasa.Tracker `json:"tracker"`
 	// Domain is the content of the current domain.yml of the currently running model.
 	Domain rasa.Domain `json:"domain"`
+	// Version of Rasa Open Source which sent the request, e.g. `3.6.2`.
+	Version string `json:"version"`
+	// Protocol of the request which is detected when the request is parsed.
+	Protocol Protocol `json:"-"`
+}
+
+// MajorVersion returns the major version of Rasa Open Source which sent the request or `0` if the version is unknown.
+func (actionRequest *CustomActionRequest) MajorVersion() int {
+	major, _, _ := strings.Cut(actionRequest.Version, ".")
+
+	version, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+
+	return version
 }
@@ -99,9 +99,10 @@ func TestParsedDomainSlots(t *testing.T) {
 
 	domain := parsed.Domain
 
+	categories := []interface{}{"dialogflow", "luis", "__other__"}
 	expectedSlots := map[string]rasa.Slot{
 		"budget":      {Type: "rasa.core.slots.UnfeaturizedSlot", AutoFill: true},
-		"current_api": {Type: "rasa.core.slots.CategoricalSlot", AutoFill: true},
+		"current_api": {Type: "rasa.core.slots.CategoricalSlot", AutoFill: true, Values: categories},
 		"name":        {Type: "rasa.core.slots.TextSlot", AutoFill: true},
 		"onboarding":  {Type: "rasa.core.slots.BooleanSlot", AutoFill: true}}
 	assert.Equal(t, domain.Slots, expectedSlots)
@@ -205,6 +206,39 @@ func TestParsedActiveForm(t *testing.T) {
 
 	assert.Equal(t, rasa.ActiveLoop{Name: "my-form", Validate: true,
 		Rejected: false, TriggerMessage: events.ParseData{}}, parsed.Tracker.ActiveLoop)
+	assert.Equal(t, ProtocolActiveLoop, parsed.Protocol)
+}
+
+func TestParsedRasa1Request(t *testing.T) {
+	parsed, err := parsedJSON("request_rasa_1.json")
+	assert.Nil(t, err)
+
+	assert.Equal(t, ProtocolActiveForm, parsed.Protocol)
+	assert.Equal(t, rasa.ActiveLoop{Name: "restaurant_form", Validate: false,
+		TriggerMessage: events.ParseData{Intent: events.IntentParseResult{Name: "request_restaurant"}}},
+		parsed.Tracker.ActiveLoop)
+	assert.Equal(t, map[string]map[string]interface{}{"restaurant_form": {}}, parsed.Domain.Forms)
+	assert.Equal(t, []rasa.Response{{Text: "What cuisine?"}}, parsed.Domain.Responses["utter_ask_cuisine"])
+	assert.Equal(t, []events.Event{
+		&events.Form{Base: events.Base{Type: "form", Timestamp: 1593400000.1}, Name: "restaurant_form"},
+		&events.FormValidation{Base: events.Base{Type: "form_validation", Timestamp: 1593400000.2}},
+	}, parsed.Tracker.Events)
+}
+
+func TestDetectProtocol(t *testing.T) {
+	for body, expected := range map[string]Protocol{
+		`{"tracker": {"active_loop": {}}}`:                     ProtocolActiveLoop,
+		`{"tracker": {"active_form": {}}}`:                     ProtocolActiveForm,
+		`{"tracker": {"active_form": null}}`:                   ProtocolActiveForm,
+		`{"version": "1.10.2", "tracker": {}}`:                 ProtocolActiveForm,
+		`{"version": "2.8.0", "tracker": {"active_loop": {}}}`: ProtocolActiveLoop,
+		`{"next_action": "action_hello_world"}`:                ProtocolActiveLoop,
+	} {
+		parsed, err := Parsed(strings.NewReader(body))
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, parsed.Protocol, body)
+	}
 }
 
 func TestParsedReminderEvents(t *testing.T) {
@@ -220,6 +254,27 @@ func TestParsedReminderEvents(t *testing.T) {
 	assert.Equal(t, expectedEvents, parsed.Tracker.Events)
 }
 
+func TestParsedRasa3Request(t *testing.T) {
+	parsed, err := parsedJSON("request_rasa_3.json")
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, parsed.MajorVersion())
+	assert.Equal(t, rasa.LatestAction{ActionName: "action_listen"}, parsed.Tracker.LatestAction)
+	assert.Equal(t, []rasa.SlotMapping{{Type: rasa.MappingFromEntity, Entity: "cuisine",
+		Conditions: []rasa.MappingCondition{{ActiveLoop: "restaurant_form", RequestedSlot: "cuisine"}}}},
+		parsed.Domain.Slots["cuisine"].Mappings)
+	assert.Equal(t, []string{"cuisine"}, parsed.Domain.RequiredSlots("restaurant_form"))
+	assert.Equal(t, &events.Action{Base: events.Base{Type: "action", Timestamp: 1693399999.1},
+		Name: "action_listen"}, parsed.Tracker.Events[0])
+}
+
+func TestMajorVersion(t *testing.T) {
+	for version, expected := range map[string]int{"3.6.2": 3, "2.8.0": 2, "1": 1, "": 0, "main": 0} {
+		actionRequest := CustomActionRequest{Version: version}
+		assert.Equal(t, expected, actionRequest.MajorVersion(), version)
+	}
+}
+
 func parsedJSON(path string) (CustomActionRequest, error) {
 	const testDataDir = "testdata"
 	fullPath := filepath.Join(testDataDir, path)
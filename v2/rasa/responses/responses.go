@@ -41,8 +41,10 @@ type Message struct {
 	// Text of the message.
 	Text string `json:"text"`
 	// Template is the response from the `domain.yml` which should be triggered instead of the hard coding the message
-	// content as part of the custom action.
+	// content as part of the custom action. Rasa Open Source 3 renamed this field to `Response`.
 	Template string `json:"template,omitempty"`
+	// Response is the response from the `domain.yml` which should be triggered (Rasa Open Source 3 and later).
+	Response string `json:"response,omitempty"`
 	// Elements of the message.
 	Elements []interface{} `json:"elements,omitempty"`
 	// QuickReplies for the user.
@@ -56,3 +58,27 @@ type Message struct {
 	// Custom can be used to send custom payloads to the user.
 	Custom interface{} `json:"custom,omitempty"`
 }
+
+// ForRasaVersion returns a copy of the message which refers to responses from the domain in the way the given major
+// version of Rasa Open Source expects: `Response` for Rasa Open Source 3 and later and `Template` for earlier
+// versions. The message is returned as is if the version is unknown (`0`).
+func (message *Message) ForRasaVersion(majorVersion int) *Message {
+	converted := *message
+
+	switch {
+	case majorVersion >= 3:
+		if converted.Response == "" {
+			converted.Response = converted.Template
+		}
+
+		converted.Template = ""
+	case majorVersion > 0:
+		if converted.Template == "" {
+			converted.Template = converted.Response
+		}
+
+		converted.Response = ""
+	}
+
+	return &converted
+}
@@ -0,0 +1,284 @@
+package rasa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrSlotNotSet is returned by the typed slot accessors of the `Tracker` if the slot is missing or has no value.
+var ErrSlotNotSet = errors.New("slot is not set")
+
+// SlotTypeError happens when the value of a slot cannot be converted to the requested type.
+type SlotTypeError struct {
+	name       string
+	value      interface{}
+	targetType string
+}
+
+func (e *SlotTypeError) Error() string {
+	return fmt.Sprintf("slot '%s' has value '%v' (%T) which cannot be used as %s.", e.name, e.value, e.value,
+		e.targetType)
+}
+
+func (tracker *Tracker) slotValue(name string) (interface{}, error) {
+	value, ok := tracker.Slots[name]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrSlotNotSet, name)
+	}
+
+	return value, nil
+}
+
+// SlotString returns the value of a slot as string. Numbers and booleans are formatted as strings.
+func (tracker *Tracker) SlotString(name string) (string, error) {
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return "", err
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(typed), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	default:
+		return "", &SlotTypeError{name, value, "string"}
+	}
+}
+
+// SlotFloat returns the value of a slot as float. Strings which contain numbers are converted.
+func (tracker *Tracker) SlotFloat(name string) (float64, error) {
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := AsFloat(value)
+	if !ok {
+		return 0, &SlotTypeError{name, value, "float"}
+	}
+
+	return number, nil
+}
+
+// SlotInt returns the value of a slot as integer. Rasa Open Source sends numbers as floats which are converted if
+// they don't have a fractional part. Strings which contain numbers are converted.
+func (tracker *Tracker) SlotInt(name string) (int, error) {
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := AsInt(value)
+	if !ok {
+		return 0, &SlotTypeError{name, value, "int"}
+	}
+
+	return number, nil
+}
+
+// AsFloat converts numbers and strings which contain numbers to `float64`. It returns `false` for other values and
+// for `NaN`.
+func AsFloat(value interface{}) (float64, bool) {
+	var (
+		number float64
+		ok     bool
+	)
+
+	switch typed := value.(type) {
+	case float64:
+		number, ok = typed, true
+	case float32:
+		number, ok = float64(typed), true
+	case int:
+		number, ok = float64(typed), true
+	case int64:
+		number, ok = float64(typed), true
+	case json.Number:
+		parsed, err := typed.Float64()
+		number, ok = parsed, err == nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		number, ok = parsed, err == nil
+	}
+
+	return number, ok && !math.IsNaN(number)
+}
+
+// AsInt converts whole numbers and strings which contain whole numbers to `int`. Rasa Open Source sends numbers as
+// floats. It returns `false` for numbers with a fractional part and numbers which don't fit into an `int`.
+func AsInt(value interface{}) (int, bool) {
+	number, ok := AsFloat(value)
+
+	// `float64(math.MaxInt)` rounds up, so the upper bound is exclusive.
+	if !ok || number != math.Trunc(number) || number < float64(math.MinInt) || number >= -float64(math.MinInt) {
+		return 0, false
+	}
+
+	return int(number), true
+}
+
+// SlotBool returns the value of a slot as boolean. Strings like `true` or `false` are converted.
+func (tracker *Tracker) SlotBool(name string) (bool, error) {
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return false, err
+	}
+
+	switch typed := value.(type) {
+	case bool:
+		return typed, nil
+	case string:
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(typed)); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return false, &SlotTypeError{name, value, "bool"}
+}
+
+// SlotList returns the value of a list slot.
+func (tracker *Tracker) SlotList(name string) ([]interface{}, error) {
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed, nil
+	case []string:
+		list := make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			list = append(list, item)
+		}
+
+		return list, nil
+	default:
+		return nil, &SlotTypeError{name, value, "list"}
+	}
+}
+
+// SlotAs returns the value of a slot converted to `T`. Values are converted like JSON values, e.g. to decode slots
+// which contain objects into structs.
+func SlotAs[T any](tracker *Tracker, name string) (T, error) {
+	var converted T
+
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return converted, err
+	}
+
+	if typed, ok := value.(T); ok {
+		return typed, nil
+	}
+
+	serialized, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(serialized, &converted)
+	}
+
+	if err != nil {
+		return converted, &SlotTypeError{name, value, fmt.Sprintf("%T", converted)}
+	}
+
+	return converted, nil
+}
+
+// CheckSlot checks that the slot is declared in the domain and that its value matches the declared slot type.
+// Slots without value are valid.
+func (tracker *Tracker) CheckSlot(name string, domain *Domain) error {
+	declared, ok := domain.Slots[name]
+	if !ok {
+		return fmt.Errorf("slot '%s' is not declared in the domain", name)
+	}
+
+	value, err := tracker.slotValue(name)
+	if err != nil {
+		return nil
+	}
+
+	if !matchesSlotType(declared, value) {
+		return &SlotTypeError{name, value, fmt.Sprintf("value of a '%s' slot", declared.Type)}
+	}
+
+	return nil
+}
+
+func matchesSlotType(slot Slot, value interface{}) bool {
+	switch SlotTypeName(slot.Type) {
+	case "text":
+		_, ok := value.(string)
+		return ok
+	case "categorical":
+		return matchesCategory(slot.Values, value)
+	case "float":
+		_, ok := AsFloat(value)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "list":
+		switch value.(type) {
+		case []interface{}, []string:
+			return true
+		default:
+			return false
+		}
+	default:
+		return true
+	}
+}
+
+// SlotTypeName returns the slot type as used in the `domain.yml`, e.g. `text`. Rasa Open Source sends the slot types
+// as class paths, e.g. `rasa.core.slots.TextSlot` (Rasa Open Source 1) or `rasa.shared.core.slots.TextSlot`
+// (Rasa Open Source 2 and later). Custom slot types are returned unchanged.
+func SlotTypeName(slotType string) string {
+	if !strings.Contains(slotType, ".") {
+		return slotType
+	}
+
+	switch slotType[strings.LastIndex(slotType, ".")+1:] {
+	case "TextSlot":
+		return "text"
+	case "CategoricalSlot":
+		return "categorical"
+	case "FloatSlot":
+		return "float"
+	case "BooleanSlot":
+		return "bool"
+	case "ListSlot":
+		return "list"
+	case "AnySlot":
+		return "any"
+	case "UnfeaturizedSlot":
+		return "unfeaturized"
+	default:
+		return slotType
+	}
+}
+
+// matchesCategory checks if the value is one of the categories. Categories and value are compared as strings so that
+// e.g. the category `1` matches the value `1.0` which Rasa Open Source sends.
+func matchesCategory(categories []interface{}, value interface{}) bool {
+	if len(categories) == 0 {
+		return true
+	}
+
+	category := fmt.Sprint(value)
+	for _, candidate := range categories {
+		if strings.EqualFold(fmt.Sprint(candidate), category) {
+			return true
+		}
+	}
+
+	return false
+}
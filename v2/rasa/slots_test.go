@@ -0,0 +1,204 @@
+package rasa
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func slotTracker() *Tracker {
+	return &Tracker{Slots: map[string]interface{}{
+		"city":        "Berlin",
+		"guests":      4.0,
+		"guests_str":  " 4 ",
+		"price":       "12.5",
+		"fraction":    2.5,
+		"outdoor":     true,
+		"outdoor_str": "false",
+		"stops":       []interface{}{"Paris", "Rome"},
+		"nothing":     nil,
+		"address":     map[string]interface{}{"street": "Main St", "number": 1.0},
+	}}
+}
+
+func TestSlotString(t *testing.T) {
+	tracker := slotTracker()
+
+	city, err := tracker.SlotString("city")
+	assert.Nil(t, err)
+	assert.Equal(t, "Berlin", city)
+
+	guests, err := tracker.SlotString("guests")
+	assert.Nil(t, err)
+	assert.Equal(t, "4", guests)
+
+	_, err = tracker.SlotString("stops")
+	assert.IsType(t, &SlotTypeError{}, err)
+	assert.Equal(t, "slot 'stops' has value '[Paris Rome]' ([]interface {}) which cannot be used as string.",
+		err.Error())
+}
+
+func TestMissingSlots(t *testing.T) {
+	tracker := slotTracker()
+
+	_, err := tracker.SlotString("nothing")
+	assert.True(t, errors.Is(err, ErrSlotNotSet))
+
+	_, err = tracker.SlotFloat("unknown")
+	assert.True(t, errors.Is(err, ErrSlotNotSet))
+	assert.Equal(t, "slot is not set: 'unknown'", err.Error())
+
+	_, err = SlotAs[string](&Tracker{}, "unknown")
+	assert.True(t, errors.Is(err, ErrSlotNotSet))
+}
+
+func TestSlotFloat(t *testing.T) {
+	tracker := slotTracker()
+
+	guests, err := tracker.SlotFloat("guests")
+	assert.Nil(t, err)
+	assert.Equal(t, 4.0, guests)
+
+	price, err := tracker.SlotFloat("price")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.5, price)
+
+	_, err = tracker.SlotFloat("city")
+	assert.IsType(t, &SlotTypeError{}, err)
+}
+
+func TestSlotInt(t *testing.T) {
+	tracker := slotTracker()
+
+	guests, err := tracker.SlotInt("guests")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, guests)
+
+	guests, err = tracker.SlotInt("guests_str")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, guests)
+
+	_, err = tracker.SlotInt("fraction")
+	assert.IsType(t, &SlotTypeError{}, err)
+
+	tracker.Slots["huge"] = math.Pow(2, 63)
+	_, err = tracker.SlotInt("huge")
+	assert.IsType(t, &SlotTypeError{}, err)
+}
+
+func TestAsFloatRejectsNaN(t *testing.T) {
+	_, ok := AsFloat("NaN")
+	assert.False(t, ok)
+
+	_, ok = AsFloat(math.NaN())
+	assert.False(t, ok)
+
+	number, ok := AsFloat(" 2.5 ")
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, number)
+}
+
+func TestSlotBool(t *testing.T) {
+	tracker := slotTracker()
+
+	outdoor, err := tracker.SlotBool("outdoor")
+	assert.Nil(t, err)
+	assert.True(t, outdoor)
+
+	outdoor, err = tracker.SlotBool("outdoor_str")
+	assert.Nil(t, err)
+	assert.False(t, outdoor)
+
+	_, err = tracker.SlotBool("city")
+	assert.IsType(t, &SlotTypeError{}, err)
+}
+
+func TestSlotList(t *testing.T) {
+	tracker := slotTracker()
+
+	stops, err := tracker.SlotList("stops")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Paris", "Rome"}, stops)
+
+	tracker.Slots["stops"] = []string{"Oslo"}
+	stops, err = tracker.SlotList("stops")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Oslo"}, stops)
+
+	_, err = tracker.SlotList("city")
+	assert.IsType(t, &SlotTypeError{}, err)
+}
+
+func TestSlotAs(t *testing.T) {
+	type address struct {
+		Street string `json:"street"`
+		Number int    `json:"number"`
+	}
+
+	tracker := slotTracker()
+
+	parsed, err := SlotAs[address](tracker, "address")
+	assert.Nil(t, err)
+	assert.Equal(t, address{Street: "Main St", Number: 1}, parsed)
+
+	stops, err := SlotAs[[]string](tracker, "stops")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Paris", "Rome"}, stops)
+
+	city, err := SlotAs[string](tracker, "city")
+	assert.Nil(t, err)
+	assert.Equal(t, "Berlin", city)
+
+	_, err = SlotAs[int](tracker, "city")
+	assert.IsType(t, &SlotTypeError{}, err)
+}
+
+func TestCheckSlot(t *testing.T) {
+	tracker := slotTracker()
+	tracker.Slots["seating"] = "Outside"
+	domain := Domain{Slots: map[string]Slot{
+		"city":    {Type: "text"},
+		"guests":  {Type: "rasa.core.slots.FloatSlot"},
+		"outdoor": {Type: "bool"},
+		"stops":   {Type: "list"},
+		"nothing": {Type: "float"},
+		"seating": {Type: "categorical", Values: []interface{}{"inside", "outside"}},
+		"price":   {Type: "bool"},
+		"address": {Type: "any"},
+	}}
+
+	for _, slot := range []string{"city", "guests", "outdoor", "stops", "nothing", "seating", "address"} {
+		assert.Nil(t, tracker.CheckSlot(slot, &domain), slot)
+	}
+
+	assert.IsType(t, &SlotTypeError{}, tracker.CheckSlot("price", &domain))
+	assert.NotNil(t, tracker.CheckSlot("fraction", &domain))
+
+	tracker.Slots["seating"] = "roof"
+	assert.IsType(t, &SlotTypeError{}, tracker.CheckSlot("seating", &domain))
+}
+
+func TestCheckSlotWithRasa2SlotTypes(t *testing.T) {
+	tracker := slotTracker()
+	domain := Domain{Slots: map[string]Slot{
+		"city":    {Type: "rasa.shared.core.slots.TextSlot"},
+		"guests":  {Type: "rasa.shared.core.slots.FloatSlot"},
+		"price":   {Type: "rasa.shared.core.slots.BooleanSlot"},
+		"address": {Type: "rasa.shared.core.slots.AnySlot"},
+	}}
+
+	for _, slot := range []string{"city", "guests", "address"} {
+		assert.Nil(t, tracker.CheckSlot(slot, &domain), slot)
+	}
+
+	assert.IsType(t, &SlotTypeError{}, tracker.CheckSlot("price", &domain))
+}
+
+func TestSlotTypeName(t *testing.T) {
+	assert.Equal(t, "text", SlotTypeName("rasa.core.slots.TextSlot"))
+	assert.Equal(t, "list", SlotTypeName("rasa.shared.core.slots.ListSlot"))
+	assert.Equal(t, "float", SlotTypeName("float"))
+	assert.Equal(t, "addons.slots.CustomSlot", SlotTypeName("addons.slots.CustomSlot"))
+}
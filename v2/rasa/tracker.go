@@ -28,6 +28,9 @@ type Tracker struct {
 	ActiveLoop ActiveLoop `json:"active_loop"`
 	// LatestActionName is the name of the last action the bot executed.
 	LatestActionName string `json:"latest_action_name"`
+	// LatestAction is the last action the bot executed. End-to-end trained bots might predict a text instead of an
+	// action name.
+	LatestAction LatestAction `json:"latest_action"`
 	// LatestInputChannel is the name of the last channel (e.g. Slack, Telegram) which the user used to speak to the
 	// assistant.
 	LatestInputChannel string `json:"latest_input_channel"`
@@ -45,6 +48,14 @@ type ActiveLoop struct {
 	TriggerMessage events.ParseData `json:"trigger_message"`
 }
 
+// LatestAction describes the last action the bot executed.
+type LatestAction struct {
+	// ActionName is the name of the action.
+	ActionName string `json:"action_name,omitempty"`
+	// ActionText is the text which the bot predicted in case of end-to-end predictions.
+	ActionText string `json:"action_text,omitempty"`
+}
+
 // EmptyTracker returns a new tracker with its default default values set.
 func EmptyTracker() *Tracker {
 	tracker := &Tracker{ActiveLoop: ActiveLoop{Validate: true}}
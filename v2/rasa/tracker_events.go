@@ -0,0 +1,198 @@
+package rasa
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+)
+
+const (
+	actionListen       = "action_listen"
+	actionSessionStart = "action_session_start"
+)
+
+// Apply appends the events to the conversation history and updates the state of the tracker in the same way as
+// Rasa Open Source does, e.g. slot values, the active loop and the latest message. Slots are reset to `nil` and not
+// carried over to new sessions. Use `ApplyWithDomain` to respect the initial slot values and the session
+// configuration of the domain.
+// Note that `RawEvents` are not updated.
+func (tracker *Tracker) Apply(newEvents ...events.Event) {
+	tracker.ApplyWithDomain(&Domain{}, newEvents...)
+}
+
+// ApplyWithDomain appends the events to the conversation history and updates the state of the tracker. Slots are
+// reset to their initial values of the domain and carried over to new sessions if the session configuration says so.
+func (tracker *Tracker) ApplyWithDomain(domain *Domain, newEvents ...events.Event) {
+	tracker.Init()
+
+	for _, event := range newEvents {
+		tracker.Events = append(tracker.Events, event)
+
+		switch event.(type) {
+		case *events.UserUtteranceReverted, *events.ActionReverted:
+			tracker.Replay(domain)
+		default:
+			tracker.applyEvent(domain, event)
+		}
+	}
+}
+
+// Replay rebuilds the state of the tracker from its `Events`.
+func (tracker *Tracker) Replay(domain *Domain) {
+	tracker.Init()
+	tracker.reset(domain)
+
+	for _, event := range tracker.appliedEvents(domain.SessionConfig.CarryOverSlotsToNewSession) {
+		tracker.applyEvent(domain, event)
+	}
+}
+
+// AppliedEvents returns the events of the current session which were not reverted by `UserUtteranceReverted` or
+// `ActionReverted` events.
+func (tracker *Tracker) AppliedEvents() []events.Event {
+	return tracker.appliedEvents(false)
+}
+
+func (tracker *Tracker) appliedEvents(carryOverSlots bool) []events.Event {
+	var applied []events.Event
+
+	for _, event := range tracker.Events {
+		switch event.(type) {
+		case *events.Restarted:
+			applied = []events.Event{event}
+		case *events.SessionStarted:
+			if carryOverSlots {
+				applied = append(slotEvents(applied), event)
+			} else {
+				applied = []events.Event{event}
+			}
+		case *events.ActionReverted:
+			applied = undoTillPrevious[*events.Action](applied)
+		case *events.UserUtteranceReverted:
+			applied = undoTillPrevious[*events.User](applied)
+			applied = undoTillPrevious[*events.Action](applied)
+		default:
+			applied = append(applied, event)
+		}
+	}
+
+	return applied
+}
+
+// undoTillPrevious removes events from the end until and including the last event of type `T`.
+func undoTillPrevious[T events.Event](applied []events.Event) []events.Event {
+	for i := len(applied) - 1; i >= 0; i-- {
+		if _, ok := applied[i].(T); ok {
+			return applied[:i]
+		}
+	}
+
+	return nil
+}
+
+// slotEvents returns the events which influence the slot values.
+func slotEvents(applied []events.Event) []events.Event {
+	var slotChanges []events.Event
+
+	for _, event := range applied {
+		switch event.(type) {
+		case *events.SlotSet, *events.AllSlotsReset:
+			slotChanges = append(slotChanges, event)
+		}
+	}
+
+	return slotChanges
+}
+
+func (tracker *Tracker) applyEvent(domain *Domain, event events.Event) {
+	switch typed := event.(type) {
+	case *events.User:
+		tracker.LatestMessage = typed.ParseData
+		tracker.LatestMessage.Text = typed.Text
+		tracker.LatestInputChannel = typed.InputChannel
+		tracker.FollowUpAction = ""
+	case *events.EntitiesAdded:
+		tracker.LatestMessage.Entities = append(tracker.LatestMessage.Entities, typed.Entities...)
+	case *events.ActionExecutionRejected:
+		if tracker.ActiveLoop.Name == typed.Name {
+			tracker.ActiveLoop.Rejected = true
+		}
+	case *events.Action:
+		tracker.LatestActionName = typed.Name
+		tracker.LatestAction = LatestAction{ActionName: typed.Name, ActionText: typed.ActionText}
+		tracker.FollowUpAction = ""
+	case *events.SlotSet:
+		tracker.Slots[typed.Name] = typed.Value
+	case *events.AllSlotsReset:
+		tracker.resetSlots(domain)
+	case *events.Restarted:
+		tracker.reset(domain)
+		tracker.FollowUpAction = actionSessionStart
+	case *events.SessionStarted:
+		slots := tracker.Slots
+		tracker.reset(domain)
+
+		if domain.SessionConfig.CarryOverSlotsToNewSession {
+			tracker.Slots = slots
+		}
+	case *events.ActiveLoop:
+		tracker.changeLoopTo(typed.Name)
+	case *events.Form:
+		tracker.changeLoopTo(typed.Name)
+	case *events.LoopInterrupted:
+		tracker.ActiveLoop.Validate = !typed.IsInterrupted
+	case *events.FormValidation:
+		tracker.ActiveLoop.Validate = typed.Validate
+	case *events.FollowUpAction:
+		tracker.FollowUpAction = typed.Name
+	case *events.ConversationPaused:
+		tracker.Paused = true
+	case *events.ConversationResumed:
+		tracker.Paused = false
+	}
+
+	if timestamp := timestampOf(event); timestamp > tracker.LatestEventTime {
+		tracker.LatestEventTime = timestamp
+	}
+}
+
+func timestampOf(event events.Event) float64 {
+	if timestamped, ok := event.(interface{ EventTimestamp() float64 }); ok {
+		return timestamped.EventTimestamp()
+	}
+
+	return 0
+}
+
+func (tracker *Tracker) changeLoopTo(name string) {
+	if name == "" {
+		tracker.ActiveLoop = ActiveLoop{}
+		return
+	}
+
+	tracker.ActiveLoop = ActiveLoop{Name: name, Validate: true, TriggerMessage: tracker.LatestMessage}
+}
+
+// reset resets the state of the tracker like at the beginning of a conversation.
+func (tracker *Tracker) reset(domain *Domain) {
+	tracker.resetSlots(domain)
+	tracker.Paused = false
+	tracker.LatestActionName = ""
+	tracker.LatestAction = LatestAction{}
+	tracker.LatestMessage = events.ParseData{}
+	tracker.FollowUpAction = actionListen
+	tracker.ActiveLoop = ActiveLoop{}
+}
+
+// resetSlots resets all slots to their initial value.
+func (tracker *Tracker) resetSlots(domain *Domain) {
+	slots := make(map[string]interface{}, len(tracker.Slots))
+
+	for name := range tracker.Slots {
+		slots[name] = nil
+	}
+
+	for name, slot := range domain.Slots {
+		slots[name] = slot.InitialValue
+	}
+
+	tracker.Slots = slots
+}
@@ -0,0 +1,160 @@
+package rasa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+)
+
+func userMessage(text, intent string) *events.User {
+	return &events.User{Text: text, InputChannel: "rest",
+		ParseData: events.ParseData{Intent: events.IntentParseResult{Name: intent}}}
+}
+
+func TestApplySlotEvents(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(&events.SlotSet{Name: "city", Value: "Berlin"}, &events.SlotSet{Name: "guests", Value: 2.0})
+
+	assert.Equal(t, map[string]interface{}{"city": "Berlin", "guests": 2.0}, tracker.Slots)
+	assert.Len(t, tracker.Events, 2)
+
+	domain := Domain{Slots: map[string]Slot{"guests": {InitialValue: 1.0}}}
+	tracker.ApplyWithDomain(&domain, &events.AllSlotsReset{})
+
+	assert.Equal(t, map[string]interface{}{"city": nil, "guests": 1.0}, tracker.Slots)
+}
+
+func TestApplyMessagesAndActions(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(&events.FollowUpAction{Name: "action_greet"})
+	assert.Equal(t, "action_greet", tracker.FollowUpAction)
+
+	tracker.Apply(userMessage("hi", "greet"), &events.EntitiesAdded{Entities: []events.Entity{{Name: "city"}}})
+	assert.Equal(t, "greet", tracker.LatestMessage.Intent.Name)
+	assert.Equal(t, "hi", tracker.LatestMessage.Text)
+	assert.Equal(t, "rest", tracker.LatestInputChannel)
+	assert.Len(t, tracker.LatestMessage.Entities, 1)
+	assert.Empty(t, tracker.FollowUpAction)
+
+	tracker.Apply(&events.Action{Base: events.Base{Timestamp: 42}, Name: "action_greet"})
+	assert.Equal(t, "action_greet", tracker.LatestActionName)
+	assert.Equal(t, LatestAction{ActionName: "action_greet"}, tracker.LatestAction)
+	assert.Equal(t, 42.0, tracker.LatestEventTime)
+
+	tracker.Apply(&events.Action{ActionText: "Hello!"})
+	assert.Equal(t, LatestAction{ActionText: "Hello!"}, tracker.LatestAction)
+
+	tracker.Apply(&events.ConversationPaused{})
+	assert.True(t, tracker.Paused)
+
+	tracker.Apply(&events.ConversationResumed{})
+	assert.False(t, tracker.Paused)
+}
+
+func TestApplyActiveLoop(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(userMessage("book a table", "request_restaurant"), &events.ActiveLoop{Name: "restaurant_form"})
+	assert.Equal(t, ActiveLoop{Name: "restaurant_form", Validate: true,
+		TriggerMessage: events.ParseData{Text: "book a table",
+			Intent: events.IntentParseResult{Name: "request_restaurant"}}}, tracker.ActiveLoop)
+
+	tracker.Apply(&events.LoopInterrupted{IsInterrupted: true})
+	assert.False(t, tracker.ActiveLoop.Validate)
+
+	tracker.Apply(&events.ActionExecutionRejected{Action: events.Action{Name: "restaurant_form"}})
+	assert.True(t, tracker.ActiveLoop.Rejected)
+
+	tracker.Apply(&events.ActiveLoop{})
+	assert.Equal(t, ActiveLoop{}, tracker.ActiveLoop)
+
+	tracker.Apply(&events.Form{Name: "legacy_form"}, &events.FormValidation{Validate: false})
+	assert.Equal(t, "legacy_form", tracker.ActiveLoop.Name)
+	assert.False(t, tracker.ActiveLoop.Validate)
+}
+
+func TestApplyRestart(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(userMessage("hi", "greet"), &events.SlotSet{Name: "city", Value: "Berlin"},
+		&events.ActiveLoop{Name: "form"}, &events.Restarted{})
+
+	assert.Equal(t, map[string]interface{}{"city": nil}, tracker.Slots)
+	assert.Equal(t, ActiveLoop{}, tracker.ActiveLoop)
+	assert.Equal(t, events.ParseData{}, tracker.LatestMessage)
+	assert.Equal(t, "action_session_start", tracker.FollowUpAction)
+}
+
+func TestApplySessionStarted(t *testing.T) {
+	slotEvent := &events.SlotSet{Name: "city", Value: "Berlin"}
+
+	tracker := Tracker{}
+	tracker.Apply(slotEvent, &events.SessionStarted{})
+	assert.Equal(t, map[string]interface{}{"city": nil}, tracker.Slots)
+
+	domain := Domain{SessionConfig: SessionConfig{CarryOverSlotsToNewSession: true}}
+	tracker = Tracker{}
+	tracker.ApplyWithDomain(&domain, slotEvent, &events.SessionStarted{})
+	assert.Equal(t, map[string]interface{}{"city": "Berlin"}, tracker.Slots)
+
+	tracker.Replay(&domain)
+	assert.Equal(t, map[string]interface{}{"city": "Berlin"}, tracker.Slots)
+}
+
+func TestApplyUserUtteranceReverted(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(
+		&events.Action{Name: "action_listen"},
+		userMessage("hi", "greet"),
+		&events.Action{Name: "action_greet"},
+		&events.Action{Name: "action_listen"},
+		userMessage("Berlin", "inform"),
+		&events.SlotSet{Name: "city", Value: "Berlin"},
+		&events.UserUtteranceReverted{},
+	)
+
+	assert.Equal(t, "greet", tracker.LatestMessage.Intent.Name)
+	assert.Equal(t, "action_greet", tracker.LatestActionName)
+	assert.Equal(t, map[string]interface{}{"city": nil}, tracker.Slots)
+	assert.Len(t, tracker.Events, 7)
+	assert.Len(t, tracker.AppliedEvents(), 3)
+}
+
+func TestApplyActionReverted(t *testing.T) {
+	tracker := Tracker{}
+
+	tracker.Apply(
+		&events.Action{Name: "action_listen"},
+		userMessage("hi", "greet"),
+		&events.Action{Name: "action_greet"},
+		&events.SlotSet{Name: "greeted", Value: true},
+		&events.ActionReverted{},
+	)
+
+	assert.Equal(t, "action_listen", tracker.LatestActionName)
+	assert.Equal(t, map[string]interface{}{"greeted": nil}, tracker.Slots)
+}
+
+func TestReplayFromEvents(t *testing.T) {
+	tracker := Tracker{Events: []events.Event{
+		userMessage("hi", "greet"),
+		&events.SlotSet{Name: "city", Value: "Berlin"},
+		&events.Restarted{},
+		userMessage("book", "request_restaurant"),
+		&events.SlotSet{Name: "guests", Value: 4.0},
+		&events.ActiveLoop{Name: "restaurant_form"},
+		&events.Action{Name: "restaurant_form"},
+	}}
+
+	tracker.Replay(&Domain{Slots: map[string]Slot{"seating": {InitialValue: "inside"}}})
+
+	assert.Equal(t, map[string]interface{}{"guests": 4.0, "seating": "inside"}, tracker.Slots)
+	assert.Equal(t, "restaurant_form", tracker.ActiveLoop.Name)
+	assert.Equal(t, "request_restaurant", tracker.LatestMessage.Intent.Name)
+	assert.Equal(t, "restaurant_form", tracker.LatestActionName)
+	assert.Empty(t, tracker.FollowUpAction)
+}
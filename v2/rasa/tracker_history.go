@@ -0,0 +1,76 @@
+package rasa
+
+import (
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+)
+
+// SessionEvents returns the events which happened after the last `SessionStarted` or `Restarted` event.
+func (tracker *Tracker) SessionEvents() []events.Event {
+	for i := len(tracker.Events) - 1; i >= 0; i-- {
+		switch tracker.Events[i].(type) {
+		case *events.SessionStarted, *events.Restarted:
+			return tracker.Events[i+1:]
+		}
+	}
+
+	return tracker.Events
+}
+
+// SessionIntents returns the intents of the user messages in the current session in the order in which the user
+// expressed them.
+func (tracker *Tracker) SessionIntents() []string {
+	var intents []string
+
+	events.ForEach(tracker.SessionEvents(), func(message *events.User) bool {
+		intents = append(intents, message.ParseData.Intent.Name)
+		return true
+	})
+
+	return intents
+}
+
+// LatestUserMessages returns up to `n` of the latest user messages in the order in which they were sent. It returns
+// `nil` if `n` isn't positive.
+func (tracker *Tracker) LatestUserMessages(n int) []*events.User {
+	if n <= 0 {
+		return nil
+	}
+
+	messages := events.Filter[*events.User](tracker.Events)
+	if len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+
+	return messages
+}
+
+// LatestBotUtterance returns the latest message of the bot. `found` is `false` if the bot didn't send a message yet.
+func (tracker *Tracker) LatestBotUtterance() (utterance *events.Bot, found bool) {
+	return events.Latest[*events.Bot](tracker.Events)
+}
+
+// ExecutedActions returns the names of the actions which were executed in the conversation in the order of their
+// execution.
+func (tracker *Tracker) ExecutedActions() []string {
+	var actionNames []string
+
+	events.ForEach(tracker.Events, func(action *events.Action) bool {
+		actionNames = append(actionNames, action.Name)
+		return true
+	})
+
+	return actionNames
+}
+
+// ActionCount returns how many times the action with the given name was executed in the conversation.
+func (tracker *Tracker) ActionCount(actionName string) int {
+	count := 0
+
+	for _, name := range tracker.ExecutedActions() {
+		if name == actionName {
+			count++
+		}
+	}
+
+	return count
+}
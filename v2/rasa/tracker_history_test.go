@@ -0,0 +1,67 @@
+package rasa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
+)
+
+func historyTracker() *Tracker {
+	return &Tracker{Events: []events.Event{
+		&events.Action{Name: "action_session_start"},
+		&events.SessionStarted{},
+		&events.Action{Name: "action_listen"},
+		userMessage("hi", "greet"),
+		&events.Action{Name: "utter_greet"},
+		&events.Bot{Text: "Hey!"},
+		&events.Action{Name: "action_listen"},
+		userMessage("a table for two", "request_restaurant"),
+		&events.Action{Name: "action_listen"},
+		userMessage("outside", "inform"),
+	}}
+}
+
+func TestSessionEvents(t *testing.T) {
+	tracker := historyTracker()
+
+	assert.Len(t, tracker.SessionEvents(), 8)
+	assert.Equal(t, []string{"greet", "request_restaurant", "inform"}, tracker.SessionIntents())
+
+	tracker.Events = append(tracker.Events, &events.Restarted{}, userMessage("hello", "greet"))
+	assert.Equal(t, []string{"greet"}, tracker.SessionIntents())
+
+	assert.Len(t, (&Tracker{Events: []events.Event{&events.User{}}}).SessionEvents(), 1)
+}
+
+func TestLatestUserMessages(t *testing.T) {
+	tracker := historyTracker()
+
+	latest := tracker.LatestUserMessages(2)
+
+	assert.Len(t, latest, 2)
+	assert.Equal(t, "a table for two", latest[0].Text)
+	assert.Equal(t, "outside", latest[1].Text)
+	assert.Len(t, tracker.LatestUserMessages(10), 3)
+	assert.Nil(t, tracker.LatestUserMessages(0))
+	assert.Nil(t, tracker.LatestUserMessages(-1))
+}
+
+func TestLatestBotUtterance(t *testing.T) {
+	utterance, found := historyTracker().LatestBotUtterance()
+
+	assert.True(t, found)
+	assert.Equal(t, "Hey!", utterance.Text)
+
+	_, found = (&Tracker{}).LatestBotUtterance()
+	assert.False(t, found)
+}
+
+func TestExecutedActions(t *testing.T) {
+	tracker := historyTracker()
+
+	assert.Equal(t, []string{"action_session_start", "action_listen", "utter_greet", "action_listen",
+		"action_listen"}, tracker.ExecutedActions())
+	assert.Equal(t, 3, tracker.ActionCount("action_listen"))
+	assert.Equal(t, 0, tracker.ActionCount("utter_bye"))
+}
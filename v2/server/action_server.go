@@ -0,0 +1,254 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/metrics"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrAlreadyStarted is returned when `Start` is called on a server which was already started.
+var ErrAlreadyStarted = errors.New("action server was already started")
+
+// Server is an action server which executes custom actions upon request from Rasa Open Source.
+// In contrast to `Serve` it can be started without blocking, shut down gracefully and embedded in a larger process.
+type Server struct {
+	httpServer    *http.Server
+	registry      *actions.Registry
+	logger        log.FieldLogger
+	routerHooks   []func(*mux.Router)
+	routerOptions routerOptions
+	tlsFiles      tlsFiles
+	limits        limitsConfig
+	domainFile    string
+	// configErr is an error which happened while applying the options. It is returned when the server is started.
+	configErr error
+
+	mutex    sync.Mutex
+	listener net.Listener
+
+	serveResult      chan error
+	shutdownOnce     sync.Once
+	shutdownComplete chan struct{}
+	shutdownErr      error
+}
+
+// Option configures a `Server`.
+type Option func(*Server)
+
+// WithAddress sets the TCP address the server listens on, e.g. `:5055` or `127.0.0.1:8080`.
+func WithAddress(address string) Option {
+	return func(s *Server) { s.httpServer.Addr = address }
+}
+
+// WithReadTimeout sets the maximum duration for reading an entire request including its body.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(s *Server) { s.httpServer.ReadTimeout = timeout }
+}
+
+// WithReadHeaderTimeout sets the maximum duration for reading the request headers.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(s *Server) { s.httpServer.ReadHeaderTimeout = timeout }
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of the response.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(s *Server) { s.httpServer.WriteTimeout = timeout }
+}
+
+// WithIdleTimeout sets the maximum time to wait for the next request when keep-alives are enabled.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *Server) { s.httpServer.IdleTimeout = timeout }
+}
+
+// WithLogger sets the logger which the server and the action execution (see `actions.WithLogger`) use. By
+// default the standard logrus logger is used.
+func WithLogger(logger log.FieldLogger) Option {
+	return func(s *Server) { s.logger = logger }
+}
+
+// WithTLSConfig makes the server serve HTTPS using the given TLS configuration. The configuration has to contain
+// the server certificates.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(s *Server) { s.httpServer.TLSConfig = config }
+}
+
+// WithRouter allows to customize the router of the server, e.g. to add additional endpoints. The given function is
+// called after the default routes were registered.
+func WithRouter(customize func(router *mux.Router)) Option {
+	return func(s *Server) { s.routerHooks = append(s.routerHooks, customize) }
+}
+
+// WithMetrics exposes metrics about the executed custom actions in the Prometheus text format on `/metrics`.
+// You can register your own metrics with the given registry to expose them on the same endpoint.
+func WithMetrics(registry *metrics.Registry) Option {
+	return func(s *Server) { s.routerOptions.metrics = newActionMetrics(registry) }
+}
+
+// WithTracing enables OpenTelemetry tracing for requests to the `/webhook` endpoint. The trace context is extracted
+// from the request headers using the given propagator. Spans are created for parsing the request, looking up the
+// action and running it. The context which is passed to a `ContextAction` contains the span of the action run so that
+// actions can create child spans. The global tracer provider and propagator are used if `nil` is passed.
+func WithTracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(s *Server) { s.routerOptions.tracing = newWebhookTracing(provider, propagator) }
+}
+
+// WithRegistry makes the server execute the actions of the given registry. Actions can be registered with and
+// unregistered from the registry while the server is running. The custom actions which are passed to `New` are
+// added to the registry.
+func WithRegistry(registry *actions.Registry) Option {
+	return func(s *Server) { s.registry = registry }
+}
+
+// WithExecutionOptions configures how actions are executed, e.g. to set timeouts using `actions.WithTimeout`.
+func WithExecutionOptions(options ...actions.ExecutionOption) Option {
+	return func(s *Server) {
+		s.routerOptions.executionOptions = append(s.routerOptions.executionOptions, options...)
+	}
+}
+
+// New creates a new action server which serves the given custom actions.
+// The server listens on port `DefaultPort` unless configured differently. Authentication and TLS are configured from
+// the environment variables `RASA_SDK_TOKEN`, `RASA_SDK_HMAC_SECRET`, `RASA_SDK_TLS_CERT_FILE`,
+// `RASA_SDK_TLS_KEY_FILE` and `RASA_SDK_TLS_CLIENT_CA_FILE` if they are set. Options take precedence over environment
+// variables. Errors in the configuration, e.g. missing certificate files or several actions with the same name, are
+// returned by `Start`.
+func New(customActions []actions.Action, options ...Option) *Server {
+	actionServer := &Server{
+		httpServer:       &http.Server{Addr: address(DefaultPort)},
+		registry:         &actions.Registry{},
+		logger:           log.StandardLogger(),
+		serveResult:      make(chan error, 1),
+		shutdownComplete: make(chan struct{}),
+	}
+
+	for _, option := range append(optionsFromEnvironment(), options...) {
+		option(actionServer)
+	}
+
+	actionServer.routerOptions.logger = actionServer.logger
+	actionServer.configErr = actionServer.registry.Register(customActions...)
+
+	tlsConfig, err := actionServer.tlsFiles.tlsConfig(actionServer.httpServer.TLSConfig, actionServer.logger)
+	if actionServer.configErr == nil {
+		actionServer.configErr = err
+	}
+
+	actionServer.httpServer.TLSConfig = tlsConfig
+	actionServer.routerOptions.limits = newConcurrencyLimits(&actionServer.limits,
+		actionServer.routerOptions.metrics)
+
+	actionServer.httpServer.Handler = actionServer.Handler()
+
+	return actionServer
+}
+
+func optionsFromEnvironment() []Option {
+	return append(authFromEnvironment(), tlsFromEnvironment()...)
+}
+
+// Handler returns the HTTP handler which serves the routes of the action server.
+func (s *Server) Handler() http.Handler {
+	router := newRouter(s.registry, &s.routerOptions)
+
+	for _, customize := range s.routerHooks {
+		customize(router)
+	}
+
+	return router
+}
+
+// Start starts listening for requests without blocking. Errors which happen while binding the address are returned
+// directly. Use `Shutdown` to stop the server.
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listener != nil {
+		return ErrAlreadyStarted
+	}
+
+	if s.configErr != nil {
+		return s.configErr
+	}
+
+	if s.domainFile != "" {
+		if err := validateDomainFile(s.domainFile, s.registry); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+
+	s.listener = listener
+	s.logger.Infof("Action server running on %v", listener.Addr())
+
+	go func() { s.serveResult <- s.httpServer.Serve(listener) }()
+
+	return nil
+}
+
+// ListenAndServe starts the server and blocks until it fails or was shut down using `Shutdown`. In the latter case
+// it returns after all in-flight requests were drained.
+func (s *Server) ListenAndServe() error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+
+	if err := <-s.serveResult; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-s.shutdownComplete
+
+	return s.shutdownErr
+}
+
+// Shutdown gracefully shuts down the server. It stops accepting new requests and waits for in-flight requests to
+// finish until the context expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Shutting down action server.")
+
+	err := s.httpServer.Shutdown(ctx)
+
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = err
+		close(s.shutdownComplete)
+	})
+
+	return err
+}
+
+// Registry returns the registry of the actions which the server executes.
+func (s *Server) Registry() *actions.Registry {
+	return s.registry
+}
+
+// Addr returns the address the server is listening on or `nil` if the server was not started yet.
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TokenEnvironmentVariable is the environment variable which can be used to specify the token which Rasa Open
+	// Source has to send to execute actions. See `WithToken`.
+	TokenEnvironmentVariable = "RASA_SDK_TOKEN"
+	// HMACSecretEnvironmentVariable is the environment variable which can be used to specify the secret to validate
+	// request signatures with. See `WithHMACSecret`.
+	HMACSecretEnvironmentVariable = "RASA_SDK_HMAC_SECRET"
+
+	// SignatureHeader is the header which contains the HMAC SHA256 signature of the request body in the format
+	// `sha256=<hex encoded signature>`.
+	SignatureHeader = "X-Rasa-Signature"
+
+	// tokenQueryParameter is the query parameter which Rasa Open Source uses if a `token` is specified for the
+	// `action_endpoint` in the `endpoints.yml`.
+	tokenQueryParameter = "token"
+	bearerPrefix        = "Bearer "
+	signaturePrefix     = "sha256="
+)
+
+// webhookAuth contains the configured authentication methods for the `/webhook` endpoint. All configured methods
+// have to succeed for a request to be authenticated.
+type webhookAuth struct {
+	token      string
+	hmacSecret []byte
+}
+
+// WithToken requires requests to the `/webhook` endpoint to contain the given token either as bearer token in the
+// `Authorization` header or as `token` query parameter (which is what Rasa Open Source sends when a `token` is
+// configured for the `action_endpoint` in the `endpoints.yml`).
+// The token can also be set using the environment variable `RASA_SDK_TOKEN`.
+func WithToken(token string) Option {
+	return func(s *Server) { s.routerOptions.authentication().token = token }
+}
+
+// WithHMACSecret requires requests to the `/webhook` endpoint to contain a HMAC SHA256 signature of the request body
+// created with the given secret in the `X-Rasa-Signature` header.
+// The secret can also be set using the environment variable `RASA_SDK_HMAC_SECRET`.
+func WithHMACSecret(secret []byte) Option {
+	return func(s *Server) { s.routerOptions.authentication().hmacSecret = secret }
+}
+
+// authFromEnvironment returns the options to configure authentication using environment variables.
+func authFromEnvironment() []Option {
+	var options []Option
+
+	if token := os.Getenv(TokenEnvironmentVariable); token != "" {
+		options = append(options, WithToken(token))
+	}
+
+	if secret := os.Getenv(HMACSecretEnvironmentVariable); secret != "" {
+		options = append(options, WithHMACSecret([]byte(secret)))
+	}
+
+	return options
+}
+
+func (options *routerOptions) authentication() *webhookAuth {
+	if options.auth == nil {
+		options.auth = &webhookAuth{}
+	}
+
+	return options.auth
+}
+
+// authenticate rejects requests which don't pass the configured authentication with a `401`.
+func authenticate(next http.HandlerFunc, auth *webhookAuth, logger log.FieldLogger) http.HandlerFunc {
+	if auth == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if auth.token != "" && !auth.hasValidToken(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			sendJSONResponse(w, errorResponse{Error: "Missing or invalid token."}, http.StatusUnauthorized, logger)
+
+			return
+		}
+
+		if len(auth.hmacSecret) > 0 {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				sendJSONResponse(w, errorResponse{Error: "Reading request body failed."}, http.StatusBadRequest, logger)
+				return
+			}
+
+			if !auth.hasValidSignature(r.Header.Get(SignatureHeader), body) {
+				sendJSONResponse(w, errorResponse{Error: "Missing or invalid signature."}, http.StatusUnauthorized, logger)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		next(w, r)
+	}
+}
+
+func (auth *webhookAuth) hasValidToken(r *http.Request) bool {
+	provided := r.URL.Query().Get(tokenQueryParameter)
+
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, bearerPrefix) {
+		provided = strings.TrimPrefix(header, bearerPrefix)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(auth.token)) == 1
+}
+
+func (auth *webhookAuth) hasValidSignature(signatureHeader string, body []byte) bool {
+	if !strings.HasPrefix(signatureHeader, signaturePrefix) {
+		return false
+	}
+
+	provided, err := hex.DecodeString(strings.TrimPrefix(signatureHeader, signaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(provided, Signature(auth.hmacSecret, body))
+}
+
+// Signature returns the HMAC SHA256 signature of the body which is expected in the `X-Rasa-Signature` header
+// when `WithHMACSecret` is used. The header value has the format `sha256=<hex encoded signature>`.
+func Signature(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRetryAfter is the duration which clients are asked to wait before retrying when the server is saturated.
+	DefaultRetryAfter = time.Second
+
+	globalLimiterName = "global"
+)
+
+// errSaturated happens when a request can neither be executed nor queued.
+var errSaturated = errors.New("action server is saturated")
+
+// ConcurrencyLimit limits how many actions are executed at the same time.
+type ConcurrencyLimit struct {
+	// MaxConcurrent is the maximum number of actions which are executed at the same time.
+	MaxConcurrent int
+	// MaxQueued is the maximum number of requests which wait for a free execution slot. Further requests are
+	// rejected with `503`.
+	MaxQueued int
+	// QueueTimeout is the maximum duration a request waits in the queue before it's rejected with `503`.
+	// `0` means that requests wait until their context is canceled.
+	QueueTimeout time.Duration
+}
+
+// WithConcurrencyLimit limits the number of concurrently executed actions across all actions.
+func WithConcurrencyLimit(limit ConcurrencyLimit) Option {
+	return func(s *Server) { s.limits.global = &limit }
+}
+
+// WithActionConcurrencyLimit limits the number of concurrent executions of a specific action. This is applied in
+// addition to the limit set by `WithConcurrencyLimit`.
+func WithActionConcurrencyLimit(actionName string, limit ConcurrencyLimit) Option {
+	return func(s *Server) {
+		if s.limits.perAction == nil {
+			s.limits.perAction = map[string]ConcurrencyLimit{}
+		}
+
+		s.limits.perAction[actionName] = limit
+	}
+}
+
+// WithRetryAfter sets the duration which is sent in the `Retry-After` header when requests are rejected because the
+// server is saturated. The default is `DefaultRetryAfter`.
+func WithRetryAfter(retryAfter time.Duration) Option {
+	return func(s *Server) { s.limits.retryAfter = retryAfter }
+}
+
+// limitsConfig contains the configured concurrency limits.
+type limitsConfig struct {
+	global     *ConcurrencyLimit
+	perAction  map[string]ConcurrencyLimit
+	retryAfter time.Duration
+}
+
+// concurrencyLimits contains the limiters which are shared by all requests.
+type concurrencyLimits struct {
+	global     *limiter
+	perAction  map[string]*limiter
+	retryAfter time.Duration
+}
+
+func newConcurrencyLimits(config *limitsConfig, metrics *actionMetrics) *concurrencyLimits {
+	if config.global == nil && len(config.perAction) == 0 {
+		return nil
+	}
+
+	limits := &concurrencyLimits{perAction: map[string]*limiter{}, retryAfter: config.retryAfter}
+	if limits.retryAfter <= 0 {
+		limits.retryAfter = DefaultRetryAfter
+	}
+
+	if config.global != nil {
+		limits.global = newLimiter(globalLimiterName, *config.global, metrics)
+	}
+
+	for actionName, limit := range config.perAction {
+		limits.perAction[actionName] = newLimiter(actionName, limit, metrics)
+	}
+
+	return limits
+}
+
+// acquire waits for a free execution slot for the given action. The returned function has to be called to release
+// the slot once the action was executed.
+func (limits *concurrencyLimits) acquire(ctx context.Context, actionName string) (release func(), err error) {
+	if limits == nil {
+		return func() {}, nil
+	}
+
+	// The action's own limit is acquired first so that requests which queue for a saturated action don't block
+	// global slots which other actions could use in the meantime.
+	releaseAction, err := limits.perAction[actionName].acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseGlobal, err := limits.global.acquire(ctx)
+	if err != nil {
+		releaseAction()
+		return nil, err
+	}
+
+	return func() {
+		releaseGlobal()
+		releaseAction()
+	}, nil
+}
+
+func (limits *concurrencyLimits) reject(w http.ResponseWriter, actionName string, err error,
+	logger log.FieldLogger) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limits.retryAfter.Seconds()))))
+	sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("Action execution failed with error: %v", err),
+		ActionName: actionName}, http.StatusServiceUnavailable, logger)
+}
+
+// limiter is a semaphore with a bounded queue.
+type limiter struct {
+	name    string
+	limit   ConcurrencyLimit
+	slots   chan struct{}
+	metrics *actionMetrics
+
+	mutex  sync.Mutex
+	queued int
+}
+
+func newLimiter(name string, limit ConcurrencyLimit, metrics *actionMetrics) *limiter {
+	if limit.MaxConcurrent < 1 {
+		limit.MaxConcurrent = 1
+	}
+
+	return &limiter{name: name, limit: limit, slots: make(chan struct{}, limit.MaxConcurrent), metrics: metrics}
+}
+
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if !l.enqueue() {
+		l.metrics.observeShedRequest(l.name)
+		return nil, fmt.Errorf("%w: limit '%s' reached", errSaturated, l.name)
+	}
+	defer l.dequeue()
+
+	var timeout <-chan time.Time
+
+	if l.limit.QueueTimeout > 0 {
+		timer := time.NewTimer(l.limit.QueueTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timeout:
+		l.metrics.observeShedRequest(l.name)
+		return nil, fmt.Errorf("%w: timed out waiting for limit '%s'", errSaturated, l.name)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *limiter) release() { <-l.slots }
+
+func (l *limiter) enqueue() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.queued >= l.limit.MaxQueued {
+		return false
+	}
+
+	l.queued++
+	l.metrics.observeQueueDepth(l.name, l.queued)
+
+	return true
+}
+
+func (l *limiter) dequeue() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.queued--
+	l.metrics.observeQueueDepth(l.name, l.queued)
+}
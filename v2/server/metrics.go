@@ -0,0 +1,154 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/metrics"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+)
+
+const (
+	metricsPath = "/metrics"
+
+	actionLabel       = "action"
+	inputChannelLabel = "input_channel"
+	limiterLabel      = "limiter"
+
+	// maxInputChannels limits the number of input channels which are used as label values. The input channel is
+	// taken from the request payload so that anyone who can reach the server could otherwise create arbitrarily many
+	// time series. Further input channels are reported as `otherInputChannel`.
+	maxInputChannels  = 20
+	otherInputChannel = "other"
+)
+
+// actionMetrics contains the metrics which are collected for the execution of custom actions.
+type actionMetrics struct {
+	registry *metrics.Registry
+
+	executions    *metrics.CounterVec
+	rejections    *metrics.CounterVec
+	failures      *metrics.CounterVec
+	timeouts      *metrics.CounterVec
+	notFound      *metrics.CounterVec
+	parseFailures *metrics.CounterVec
+	duration      *metrics.HistogramVec
+	queueDepth    *metrics.GaugeVec
+	shedRequests  *metrics.CounterVec
+
+	inputChannelsLock sync.Mutex
+	inputChannels     map[string]struct{}
+}
+
+func newActionMetrics(registry *metrics.Registry) *actionMetrics {
+	return &actionMetrics{
+		registry: registry,
+		executions: registry.NewCounterVec("rasa_sdk_action_executions_total",
+			"Number of executed custom actions.", actionLabel, inputChannelLabel),
+		rejections: registry.NewCounterVec("rasa_sdk_action_rejections_total",
+			"Number of custom actions which rejected their execution.", actionLabel, inputChannelLabel),
+		failures: registry.NewCounterVec("rasa_sdk_action_failures_total",
+			"Number of custom actions which failed with an error or a panic.", actionLabel, inputChannelLabel),
+		timeouts: registry.NewCounterVec("rasa_sdk_action_timeouts_total",
+			"Number of custom actions which timed out.", actionLabel, inputChannelLabel),
+		notFound: registry.NewCounterVec("rasa_sdk_action_not_found_total",
+			"Number of requests for custom actions which are not registered."),
+		parseFailures: registry.NewCounterVec("rasa_sdk_request_parse_failures_total",
+			"Number of requests whose payload could not be parsed."),
+		duration: registry.NewHistogramVec("rasa_sdk_action_execution_duration_seconds",
+			"Duration of custom action executions in seconds.", metrics.DefaultBuckets, actionLabel, inputChannelLabel),
+		queueDepth: registry.NewGaugeVec("rasa_sdk_queued_requests",
+			"Number of requests waiting for a free execution slot.", limiterLabel),
+		shedRequests: registry.NewCounterVec("rasa_sdk_shed_requests_total",
+			"Number of requests which were rejected because a concurrency limit was reached.", limiterLabel),
+		inputChannels: map[string]struct{}{},
+	}
+}
+
+func (m *actionMetrics) observeQueueDepth(limiter string, depth int) {
+	if m == nil {
+		return
+	}
+
+	m.queueDepth.Set(float64(depth), limiter)
+}
+
+func (m *actionMetrics) observeShedRequest(limiter string) {
+	if m == nil {
+		return
+	}
+
+	m.shedRequests.Inc(limiter)
+}
+
+func (m *actionMetrics) observeParseFailure() {
+	if m == nil {
+		return
+	}
+
+	m.parseFailures.Inc()
+}
+
+func (m *actionMetrics) observeExecution(actionRequest *request.CustomActionRequest, duration time.Duration,
+	err error, timedOut bool) {
+	if m == nil {
+		return
+	}
+
+	// Unknown action names are not used as label since anyone who can reach the server could create arbitrarily many
+	// time series.
+	var notFound *actions.NotFoundError
+	if errors.As(err, &notFound) {
+		m.notFound.Inc()
+		return
+	}
+
+	actionName, inputChannel := m.labelsFor(actionRequest)
+
+	m.executions.Inc(actionName, inputChannel)
+	m.duration.Observe(duration.Seconds(), actionName, inputChannel)
+
+	var rejected *actions.ExecutionRejectedError
+
+	switch {
+	case timedOut:
+		m.timeouts.Inc(actionName, inputChannel)
+	case err == nil:
+	case errors.As(err, &rejected):
+		m.rejections.Inc(actionName, inputChannel)
+	default:
+		m.failures.Inc(actionName, inputChannel)
+	}
+}
+
+// observePanic counts an action execution which panicked as failure.
+func (m *actionMetrics) observePanic(actionRequest *request.CustomActionRequest, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	actionName, inputChannel := m.labelsFor(actionRequest)
+
+	m.executions.Inc(actionName, inputChannel)
+	m.duration.Observe(duration.Seconds(), actionName, inputChannel)
+	m.failures.Inc(actionName, inputChannel)
+}
+
+func (m *actionMetrics) labelsFor(actionRequest *request.CustomActionRequest) (actionName, inputChannel string) {
+	inputChannel = actionRequest.Tracker.LatestInputChannel
+
+	m.inputChannelsLock.Lock()
+	defer m.inputChannelsLock.Unlock()
+
+	if _, known := m.inputChannels[inputChannel]; !known {
+		if len(m.inputChannels) >= maxInputChannels {
+			return actionRequest.ActionToRun, otherInputChannel
+		}
+
+		m.inputChannels[inputChannel] = struct{}{}
+	}
+
+	return actionRequest.ActionToRun, inputChannel
+}
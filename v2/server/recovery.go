@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/logging"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
+)
+
+type webhookDetailsKey struct{}
+
+// webhookDetails collects information about the currently handled webhook request so that it can be logged in case
+// the request handling panics.
+type webhookDetails struct {
+	actionName     string
+	conversationID string
+}
+
+// rememberWebhookDetails stores the details of the parsed request in the request context.
+func rememberWebhookDetails(ctx context.Context, actionRequest *request.CustomActionRequest) {
+	if details, ok := ctx.Value(webhookDetailsKey{}).(*webhookDetails); ok {
+		details.actionName = actionRequest.ActionToRun
+		details.conversationID = actionRequest.Tracker.ConversationID
+	}
+}
+
+// headerRecorder records whether the response was already started.
+type headerRecorder struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (recorder *headerRecorder) WriteHeader(status int) {
+	recorder.wroteHeader = true
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *headerRecorder) Write(content []byte) (int, error) {
+	recorder.wroteHeader = true
+	return recorder.ResponseWriter.Write(content)
+}
+
+// Unwrap returns the original response writer, e.g. for `http.ResponseController`.
+func (recorder *headerRecorder) Unwrap() http.ResponseWriter { return recorder.ResponseWriter }
+
+// recoverPanics recovers panics which happen while handling a request, e.g. within the `Run` method of a custom
+// action, and turns them into a `500` response. In case the response was already started, the panic is only logged.
+func recoverPanics(next http.HandlerFunc, logger log.FieldLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		details := &webhookDetails{}
+		recorder := &headerRecorder{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			stack := debug.Stack()
+
+			// Panics of actions which run with a timeout are re-raised together with their original stack trace.
+			if actionPanic, ok := recovered.(*actions.PanicError); ok {
+				stack = actionPanic.Stack
+			}
+
+			logger.WithFields(log.Fields{
+				logging.ActionNameKey:     details.actionName,
+				logging.ConversationIDKey: details.conversationID,
+				logging.StackTraceKey:     string(stack),
+			}).Errorf("Recovered from panic during action execution: %v", recovered)
+
+			if recorder.wroteHeader {
+				return
+			}
+
+			sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("Action execution failed with panic: %v", recovered),
+				ActionName: details.actionName}, http.StatusInternalServerError, logger)
+		}()
+
+		next(recorder, r.WithContext(context.WithValue(r.Context(), webhookDetailsKey{}, details)))
+	}
+}
@@ -2,12 +2,19 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/logging"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/request"
 
 	"github.com/gorilla/mux"
@@ -17,10 +24,19 @@ import (
 // If you use a different port, remember to adapt the `action_endpoint` configuration in your `endpoints.yml`.
 const DefaultPort int = 5055
 
+// DefaultShutdownTimeout is the time `Serve` waits for in-flight requests to finish after receiving a termination
+// signal.
+const DefaultShutdownTimeout = 20 * time.Second
+
 type healthResponse struct {
 	Status string `json:"status"`
 }
 
+type actionDescription struct {
+	Name string `json:"name"`
+	actions.Metadata
+}
+
 type errorResponse struct {
 	Error      string `json:"error"`
 	ActionName string `json:"action_name"`
@@ -28,12 +44,17 @@ type errorResponse struct {
 
 // Serve runs the action server on the provided port.
 // Supplied actions will be executed upon request from Rasa Open Source.
+// Serve blocks until the server fails or the process receives `SIGINT` / `SIGTERM`. In the latter case the server
+// is shut down gracefully. Use `New` in case you need more control over the server.
 func Serve(port int, customActions ...actions.Action) {
 	setup(customActions)
 
-	err := http.ListenAndServe(address(port), GetRouter(customActions...))
+	actionServer := New(customActions, WithAddress(address(port)))
+	stopListening := shutdownOnSignal(actionServer, DefaultShutdownTimeout, os.Interrupt, syscall.SIGTERM)
 
-	tearDown(err)
+	defer stopListening()
+
+	tearDown(actionServer.ListenAndServe())
 }
 
 func setup(customActions []actions.Action) {
@@ -42,10 +63,36 @@ func setup(customActions []actions.Action) {
 }
 
 func address(port int) string {
-	log.Infof("Action server running on on port %v", port)
 	return fmt.Sprintf(":%v", port)
 }
 
+func shutdownOnSignal(actionServer *Server, timeout time.Duration, signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, 1)
+	stopped := make(chan struct{})
+
+	signal.Notify(received, signals...)
+
+	go func() {
+		select {
+		case receivedSignal := <-received:
+			actionServer.logger.Infof("Received signal '%v'. Shutting down action server.", receivedSignal)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if err := actionServer.Shutdown(ctx); err != nil {
+				actionServer.logger.Error(err)
+			}
+		case <-stopped:
+		}
+	}()
+
+	return func() {
+		signal.Stop(received)
+		close(stopped)
+	}
+}
+
 func tearDown(err error) {
 	if err != nil {
 		log.Error(err)
@@ -63,23 +110,77 @@ func logAvailableActions(customActions []actions.Action) {
 
 // GetRouter returns the routes for which the server accepts requests.
 // By default this is the `health` endpoint which can be used for health checks and
-// the `/webhook` endpoint which Rasa Open Source calls to execute a custom action.
+// the `/webhook` endpoint which Rasa Open Source calls to execute a custom action. The `/actions` endpoint lists the
+// served actions together with their metadata.
 // There should only be a need to call this if you want to add custom endpoints.
+// Authentication is configured from environment variables as described for `New`.
+// Use `New` together with the `Option`s of the server to enable optional endpoints like `/metrics`.
+// In case several actions have the same name, an error is logged and the first one is served. Use `New` to get the
+// duplicate names reported as error by `Server.Start`.
 func GetRouter(customActions ...actions.Action) http.Handler {
+	registry := &actions.Registry{}
+
+	for _, action := range customActions {
+		if err := registry.Register(action); err != nil {
+			log.WithFields(log.Fields{logging.ActionNameKey: action.Name(), logging.ErrorKey: err}).Error(
+				"Action is not served since another action has the same name.")
+		}
+	}
+
+	return New(nil, WithRegistry(registry)).Handler()
+}
+
+// routerOptions contains the configuration of the optional features of the router.
+type routerOptions struct {
+	logger  log.FieldLogger
+	metrics *actionMetrics
+	tracing *webhookTracing
+	auth    *webhookAuth
+	limits  *concurrencyLimits
+
+	domainValidation *requestDomainValidation
+
+	executionOptions []actions.ExecutionOption
+}
+
+func newRouter(registry *actions.Registry, options *routerOptions) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
-	router.HandleFunc("/health", health).Methods("GET", "OPTIONS")
-	router.HandleFunc("/webhook", runAction(customActions)).Methods("POST")
+	router.HandleFunc("/health", health(options.logger)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/webhook", traceRequests(authenticate(recoverPanics(runAction(registry, options),
+		options.logger), options.auth, options.logger), options.tracing)).Methods("POST")
+	router.HandleFunc("/actions", authenticate(listActions(registry, options.logger), options.auth,
+		options.logger)).Methods("GET")
+
+	if options.metrics != nil {
+		router.Handle(metricsPath, options.metrics.registry).Methods("GET")
+	}
 
 	return router
 }
 
-func health(w http.ResponseWriter, _ *http.Request) {
-	responseBody := healthResponse{"ok"}
+func health(logger log.FieldLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		responseBody := healthResponse{"ok"}
 
-	sendJSONResponse(w, responseBody, http.StatusOK)
+		sendJSONResponse(w, responseBody, http.StatusOK, logger)
+	}
 }
 
-func sendJSONResponse(writer http.ResponseWriter, responseBody interface{}, status int) {
+func listActions(registry *actions.Registry, logger log.FieldLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		registered := registry.Actions()
+		descriptions := make([]actionDescription, 0, len(registered))
+
+		for _, action := range registered {
+			descriptions = append(descriptions, actionDescription{Name: action.Name(),
+				Metadata: actions.MetadataOf(action)})
+		}
+
+		sendJSONResponse(w, descriptions, http.StatusOK, logger)
+	}
+}
+
+func sendJSONResponse(writer http.ResponseWriter, responseBody interface{}, status int, logger log.FieldLogger) {
 	serialized, _ := json.Marshal(responseBody)
 
 	writer.Header().Set("Content-Type", "application/json")
@@ -87,39 +188,81 @@ func sendJSONResponse(writer http.ResponseWriter, responseBody interface{}, stat
 
 	_, err := writer.Write(serialized)
 	if err != nil {
-		log.Error(err)
+		logger.Error(err)
 	}
 }
 
-func runAction(availableActions []actions.Action) func(http.ResponseWriter, *http.Request) {
+func runAction(registry *actions.Registry, options *routerOptions) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		actionRequest, err := request.Parsed(r.Body)
+		actionRequest, err := request.ParsedContext(r.Context(), r.Body)
 		if err != nil {
+			options.metrics.observeParseFailure()
 			sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("parsing body failed with error: %v", err)},
-				http.StatusBadRequest)
+				http.StatusBadRequest, options.logger)
 			return
 		}
 
-		responseBody, err := actions.ExecuteAction(&actionRequest, availableActions)
+		rememberWebhookDetails(r.Context(), &actionRequest)
+		options.domainValidation.validate(&actionRequest.Domain, registry, options.logger)
+
+		release, err := options.limits.acquire(r.Context(), actionRequest.ActionToRun)
+		if err != nil {
+			options.limits.reject(w, actionRequest.ActionToRun, err, options.logger)
+			return
+		}
+
+		// Actions which timed out might still be running. Their slots are only released once they finished.
+		timedOut := false
+		executionOptions := append(append([]actions.ExecutionOption{actions.WithLogger(options.logger)},
+			options.executionOptions...),
+			actions.WithFinishedCallback(release), actions.WithTimeoutCallback(func() { timedOut = true }))
+
+		executionStart := time.Now()
+		executed := false
+
+		// Panics are not recovered here so that `recoverPanics` can log them together with their stack trace.
+		defer func() {
+			if !executed {
+				options.metrics.observePanic(&actionRequest, time.Since(executionStart))
+			}
+		}()
+
+		responseBody, err := registry.Execute(r.Context(), &actionRequest, executionOptions...)
+		executed = true
+		options.metrics.observeExecution(&actionRequest, time.Since(executionStart), err, timedOut)
 
 		if err == nil {
-			sendJSONResponse(w, responseBody, http.StatusOK)
+			sendJSONResponse(w, responseBody, http.StatusOK, options.logger)
 			return
 		}
 
-		handleExecutionError(w, actionRequest.ActionToRun, err)
+		handleExecutionError(w, actionRequest.ActionToRun, err, options.logger)
 	}
 }
 
-func handleExecutionError(w http.ResponseWriter, actionName string, err error) {
-	switch err.(type) {
-	case *actions.NotFoundError:
-		sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("Action execution failed with error: %v.", err),
-			ActionName: actionName}, http.StatusNotFound)
-		return
-	case *actions.ExecutionRejectedError:
-		sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("Action execution failed with error: %v.", err),
-			ActionName: actionName}, http.StatusBadRequest)
-		return
+// handleExecutionError maps errors to the status codes which Rasa Open Source expects from an action server.
+// Rasa Open Source treats a `400` response containing the action name as rejection of the action execution.
+func handleExecutionError(w http.ResponseWriter, actionName string, err error, logger log.FieldLogger) {
+	var (
+		notFound *actions.NotFoundError
+		rejected *actions.ExecutionRejectedError
+		badInput *actions.BadInputError
+	)
+
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	case errors.As(err, &rejected):
+		status = http.StatusBadRequest
+	case errors.As(err, &badInput):
+		status = http.StatusUnprocessableEntity
+	default:
+		logger.WithFields(log.Fields{logging.ActionNameKey: actionName, logging.ErrorKey: err}).Error(
+			"Action execution failed.")
 	}
+
+	sendJSONResponse(w, errorResponse{Error: fmt.Sprintf("Action execution failed with error: %v", err),
+		ActionName: actionName}, status, logger)
 }
@@ -2,19 +2,35 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/actions/forms"
+	"github.com/wochinge/go-rasa-sdk/v2/internal/tracing"
+	"github.com/wochinge/go-rasa-sdk/v2/logging"
+	"github.com/wochinge/go-rasa-sdk/v2/metrics"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/events"
 	"github.com/wochinge/go-rasa-sdk/v2/rasa/responses"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHealth(t *testing.T) {
@@ -169,3 +185,994 @@ func TestServe(t *testing.T) {
 		assert.Contains(t, hook.Entries[0].Message, name)
 	}
 }
+
+type BlockingAction struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (action *BlockingAction) Run(_ *rasa.Tracker, _ *rasa.Domain, _ responses.ResponseDispatcher) []events.Event {
+	close(action.started)
+	<-action.release
+
+	return []events.Event{}
+}
+func (action *BlockingAction) Name() string { return "blocking-action" }
+
+func startTestServer(t *testing.T, customActions []actions.Action, options ...Option) *Server {
+	actionServer := New(customActions, append([]Option{WithAddress("127.0.0.1:0")}, options...)...)
+
+	if err := actionServer.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return actionServer
+}
+
+func TestServerStartAndShutdown(t *testing.T) {
+	actionServer := startTestServer(t, nil)
+
+	response, err := http.Get(fmt.Sprintf("http://%v/health", actionServer.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, actionServer.Shutdown(context.Background()))
+
+	_, err = http.Get(fmt.Sprintf("http://%v/health", actionServer.Addr()))
+	assert.NotNil(t, err)
+}
+
+func TestServerStartTwice(t *testing.T) {
+	actionServer := startTestServer(t, nil)
+	defer actionServer.Shutdown(context.Background())
+
+	assert.Equal(t, ErrAlreadyStarted, actionServer.Start())
+}
+
+func TestServerAddrBeforeStart(t *testing.T) {
+	assert.Nil(t, New(nil).Addr())
+}
+
+func TestServerShutdownDrainsInFlightRequests(t *testing.T) {
+	action := &BlockingAction{started: make(chan struct{}), release: make(chan struct{})}
+	actionServer := New([]actions.Action{action}, WithAddress("127.0.0.1:0"))
+
+	serveResult := make(chan error)
+	go func() { serveResult <- actionServer.ListenAndServe() }()
+
+	for actionServer.Addr() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	statusCode := make(chan int)
+
+	go func() {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"next_action": "%s"}`, action.Name()))
+
+		response, err := http.Post(fmt.Sprintf("http://%v/webhook", actionServer.Addr()), "application/json", body)
+		if err != nil {
+			statusCode <- 0
+			return
+		}
+		defer response.Body.Close()
+
+		statusCode <- response.StatusCode
+	}()
+
+	<-action.started
+
+	shutdownResult := make(chan error)
+	go func() { shutdownResult <- actionServer.Shutdown(context.Background()) }()
+
+	select {
+	case <-serveResult:
+		t.Fatal("server stopped before in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(action.release)
+
+	assert.Equal(t, http.StatusOK, <-statusCode)
+	assert.Nil(t, <-shutdownResult)
+	assert.Nil(t, <-serveResult)
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	action := &BlockingAction{started: make(chan struct{}), release: make(chan struct{})}
+	actionServer := startTestServer(t, []actions.Action{action})
+
+	defer close(action.release)
+
+	go func() {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"next_action": "%s"}`, action.Name()))
+
+		response, err := http.Post(fmt.Sprintf("http://%v/webhook", actionServer.Addr()), "application/json", body)
+		if err == nil {
+			response.Body.Close()
+		}
+	}()
+
+	<-action.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, actionServer.Shutdown(ctx))
+}
+
+func TestServerOptions(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	actionServer := New(nil,
+		WithAddress(":1234"),
+		WithReadTimeout(time.Second),
+		WithReadHeaderTimeout(2*time.Second),
+		WithWriteTimeout(3*time.Second),
+		WithIdleTimeout(4*time.Second),
+		WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+		WithLogger(logger))
+
+	assert.Equal(t, ":1234", actionServer.httpServer.Addr)
+	assert.Equal(t, time.Second, actionServer.httpServer.ReadTimeout)
+	assert.Equal(t, 2*time.Second, actionServer.httpServer.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, actionServer.httpServer.WriteTimeout)
+	assert.Equal(t, 4*time.Second, actionServer.httpServer.IdleTimeout)
+	assert.Equal(t, uint16(tls.VersionTLS12), actionServer.httpServer.TLSConfig.MinVersion)
+
+	assert.Nil(t, actionServer.Shutdown(context.Background()))
+	assert.Equal(t, 1, len(hook.AllEntries()))
+}
+
+func TestServerDefaultAddress(t *testing.T) {
+	assert.Equal(t, ":5055", New(nil).httpServer.Addr)
+}
+
+func TestServerWithCustomRoute(t *testing.T) {
+	actionServer := New(nil, WithRouter(func(router *mux.Router) {
+		router.HandleFunc("/version", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}))
+
+	request, err := http.NewRequest("GET", "/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	actionServer.Handler().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusTeapot, response.Code)
+}
+
+func TestShutdownOnSignal(t *testing.T) {
+	actionServer := New(nil, WithAddress("127.0.0.1:0"))
+
+	serveResult := make(chan error)
+	go func() { serveResult <- actionServer.ListenAndServe() }()
+
+	for actionServer.Addr() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stop := shutdownOnSignal(actionServer, time.Second, syscall.SIGUSR1)
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-serveResult:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server was not shut down")
+	}
+}
+
+type requestContextKey string
+
+type ContextAwareTestAction struct{}
+
+func (action *ContextAwareTestAction) RunContext(ctx context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	_ responses.ResponseDispatcher) []events.Event {
+	return []events.Event{&events.SlotSet{Name: "from_context", Value: ctx.Value(requestContextKey("key"))}}
+}
+func (action *ContextAwareTestAction) Name() string { return "test-context-action" }
+
+func TestRunActionPassesRequestContext(t *testing.T) {
+	body := []byte(`{"next_action": "test-context-action"}`)
+	ctx := context.WithValue(context.Background(), requestContextKey("key"), "value")
+
+	request, err := http.NewRequestWithContext(ctx, "POST", "/webhook", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler := GetRouter(actions.Contextual(&ContextAwareTestAction{}))
+
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	expectedResponse := `{"events":[{"event":"slot","name":"from_context","value":"value"}],"responses":[]}`
+	assert.Equal(t, expectedResponse, response.Body.String())
+}
+
+type FailingAction struct {
+	err error
+}
+
+func (action *FailingAction) RunWithError(_ context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	_ responses.ResponseDispatcher) ([]events.Event, error) {
+	return nil, action.err
+}
+func (action *FailingAction) Name() string { return "test-failing" }
+
+func TestActionExecutionErrors(t *testing.T) {
+	tests := []struct {
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{actions.Reject("not now"), http.StatusBadRequest,
+			`{"error":"Action execution failed with error: action 'test-failing' rejected execution: not now.",` +
+				`"action_name":"test-failing"}`},
+		{actions.BadInput(errors.New("missing slot")), http.StatusUnprocessableEntity,
+			`{"error":"Action execution failed with error: action 'test-failing' received invalid input: ` +
+				`missing slot.","action_name":"test-failing"}`},
+		{errors.New("backend down"), http.StatusInternalServerError,
+			`{"error":"Action execution failed with error: action 'test-failing' failed: backend down.",` +
+				`"action_name":"test-failing"}`},
+	}
+
+	for _, testCase := range tests {
+		body := []byte(`{"next_action": "test-failing"}`)
+
+		request, err := http.NewRequest("POST", "/webhook", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		response := httptest.NewRecorder()
+		handler := GetRouter(actions.Fallible(&FailingAction{err: testCase.err}))
+
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, testCase.expectedStatus, response.Code)
+		assert.Equal(t, testCase.expectedBody, response.Body.String())
+		assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	}
+}
+
+func TestHandleUnknownExecutionError(t *testing.T) {
+	response := httptest.NewRecorder()
+
+	handleExecutionError(response, "some-action", errors.New("unexpected"), logrus.StandardLogger())
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, `{"error":"Action execution failed with error: unexpected","action_name":"some-action"}`,
+		response.Body.String())
+}
+
+type PanickingAction struct{}
+
+func (action *PanickingAction) Run(_ *rasa.Tracker, _ *rasa.Domain, _ responses.ResponseDispatcher) []events.Event {
+	panic("something went terribly wrong")
+}
+func (action *PanickingAction) Name() string { return "test-panic" }
+
+func TestRunActionRecoversPanic(t *testing.T) {
+	body := []byte(`{"next_action": "test-panic", "tracker": {"sender_id": "some-user"}}`)
+
+	request, err := http.NewRequest("POST", "/webhook", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := test.NewGlobal()
+	response := httptest.NewRecorder()
+	handler := GetRouter(&PanickingAction{})
+
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+	expectedResponse := `{"error":"Action execution failed with panic: something went terribly wrong",` +
+		`"action_name":"test-panic"}`
+	assert.Equal(t, expectedResponse, response.Body.String())
+
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	assert.Equal(t, "test-panic", entry.Data[logging.ActionNameKey])
+	assert.Equal(t, "some-user", entry.Data[logging.ConversationIDKey])
+	assert.Contains(t, entry.Data[logging.StackTraceKey], "PanickingAction")
+}
+
+func TestWebhookUsesConfiguredLogger(t *testing.T) {
+	globalHook := test.NewGlobal()
+	logger, hook := test.NewNullLogger()
+	handler := New([]actions.Action{&PanickingAction{}, actions.Fallible(&FailingAction{err: errors.New("fail")})},
+		WithLogger(logger)).Handler()
+
+	postWebhook(t, handler, `{"next_action": "test-panic"}`)
+
+	assert.Equal(t, "test-panic", hook.LastEntry().Data[logging.ActionNameKey])
+	assert.Contains(t, hook.LastEntry().Message, "Recovered from panic")
+
+	postWebhook(t, handler, `{"next_action": "test-failing"}`)
+
+	assert.Equal(t, "Action execution failed.", hook.LastEntry().Message)
+
+	postWebhook(t, handler, `{"next_action": "unknown"}`)
+
+	assert.Equal(t, "Requested action not found.", hook.LastEntry().Message)
+	assert.Empty(t, globalHook.AllEntries())
+}
+
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (writer failingWriter) Write([]byte) (int, error) { return 0, errors.New("connection closed") }
+
+func TestSendJSONResponseLogsWriteErrorsWithLogger(t *testing.T) {
+	globalHook := test.NewGlobal()
+	logger, hook := test.NewNullLogger()
+
+	sendJSONResponse(failingWriter{httptest.NewRecorder()}, healthResponse{"ok"}, http.StatusOK, logger)
+
+	assert.Equal(t, "connection closed", hook.LastEntry().Message)
+	assert.Empty(t, globalHook.AllEntries())
+}
+
+func TestRecoverPanicsRepanicsOnAbort(t *testing.T) {
+	handler := recoverPanics(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) },
+		logrus.StandardLogger())
+
+	request, err := http.NewRequest("POST", "/webhook", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { handler(httptest.NewRecorder(), request) })
+}
+
+func TestRecoverPanicsAfterResponseWasStarted(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	handler := recoverPanics(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"events":`))
+		panic("serialization failed")
+	}, logger)
+
+	request, err := http.NewRequest("POST", "/webhook", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"events":`, response.Body.String())
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+}
+
+func postWebhook(t *testing.T, handler http.Handler, body string) *httptest.ResponseRecorder {
+	request, err := http.NewRequest("POST", "/webhook", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestMetrics(t *testing.T) {
+	blocking := &BlockingAction{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(blocking.release)
+
+	handler := New([]actions.Action{&TestAction{name: "test-action"}, &RejectingAction{name: "test-reject"},
+		actions.Fallible(&FailingAction{err: errors.New("fail")}), &PanickingAction{}, blocking},
+		WithMetrics(metrics.NewRegistry()),
+		WithExecutionOptions(actions.WithActionTimeout(blocking.Name(), 10*time.Millisecond))).Handler()
+
+	withChannel := `{"next_action": "%s", "tracker": {"latest_input_channel": "slack"}}`
+
+	postWebhook(t, handler, fmt.Sprintf(withChannel, "test-action"))
+	postWebhook(t, handler, fmt.Sprintf(withChannel, "test-action"))
+	postWebhook(t, handler, fmt.Sprintf(withChannel, "test-reject"))
+	postWebhook(t, handler, fmt.Sprintf(withChannel, "test-failing"))
+	postWebhook(t, handler, fmt.Sprintf(withChannel, "test-panic"))
+	postWebhook(t, handler, fmt.Sprintf(withChannel, blocking.Name()))
+	postWebhook(t, handler, `{"next_action": "unknown"}`)
+	postWebhook(t, handler, `{"}`)
+
+	request, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, metrics.ContentType, response.Header().Get("Content-Type"))
+
+	body := response.Body.String()
+	expectedLines := []string{
+		`rasa_sdk_action_executions_total{action="test-action",input_channel="slack"} 2`,
+		`rasa_sdk_action_executions_total{action="test-reject",input_channel="slack"} 1`,
+		`rasa_sdk_action_rejections_total{action="test-reject",input_channel="slack"} 1`,
+		`rasa_sdk_action_failures_total{action="test-failing",input_channel="slack"} 1`,
+		`rasa_sdk_action_executions_total{action="test-panic",input_channel="slack"} 1`,
+		`rasa_sdk_action_failures_total{action="test-panic",input_channel="slack"} 1`,
+		`rasa_sdk_action_executions_total{action="blocking-action",input_channel="slack"} 1`,
+		`rasa_sdk_action_timeouts_total{action="blocking-action",input_channel="slack"} 1`,
+		`rasa_sdk_action_not_found_total 1`,
+		`rasa_sdk_request_parse_failures_total 1`,
+		`rasa_sdk_action_execution_duration_seconds_count{action="test-action",input_channel="slack"} 2`,
+	}
+
+	for _, line := range expectedLines {
+		assert.Contains(t, body, line+"\n")
+	}
+}
+
+func TestMetricsLimitInputChannels(t *testing.T) {
+	handler := New([]actions.Action{&TestAction{name: "test-action"}}, WithMetrics(metrics.NewRegistry())).Handler()
+
+	for i := 0; i <= maxInputChannels; i++ {
+		postWebhook(t, handler, fmt.Sprintf(`{"next_action": "test-action", "tracker": {"latest_input_channel": "%d"}}`,
+			i))
+	}
+
+	request, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	body := response.Body.String()
+	assert.Contains(t, body, fmt.Sprintf(`rasa_sdk_action_executions_total{action="test-action",input_channel="%d"} 1`,
+		maxInputChannels-1))
+	assert.NotContains(t, body, fmt.Sprintf(`input_channel="%d"`, maxInputChannels))
+	assert.Contains(t, body, `rasa_sdk_action_executions_total{action="test-action",input_channel="other"} 1`)
+}
+
+func TestMetricsDisabledByDefault(t *testing.T) {
+	request, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	GetRouter().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+type SpanCreatingAction struct{}
+
+func (action *SpanCreatingAction) RunContext(ctx context.Context, _ *rasa.Tracker, _ *rasa.Domain,
+	_ responses.ResponseDispatcher) []events.Event {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("test").Start(ctx, "custom span")
+	span.End()
+
+	return []events.Event{}
+}
+func (action *SpanCreatingAction) Name() string { return "test-spans" }
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	handler := New([]actions.Action{actions.Contextual(&SpanCreatingAction{})},
+		WithTracing(provider, propagation.TraceContext{})).Handler()
+
+	body := `{"next_action": "test-spans", "tracker": {"sender_id": "some-user", "latest_input_channel": "slack", ` +
+		`"events": [{"event": "action", "name": "action_listen"}, {"event": "user", "text": "hi"}]}}`
+
+	request, err := http.NewRequest("POST", "/webhook", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	request.Header.Set("traceparent", fmt.Sprintf("00-%s-00f067aa0ba902b7-01", traceID))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	}
+
+	assert.Len(t, spans, 6)
+
+	webhookSpan := spans["POST /webhook"]
+	assert.Equal(t, trace.SpanKindServer, webhookSpan.SpanKind())
+	assert.Equal(t, "00f067aa0ba902b7", webhookSpan.Parent().SpanID().String())
+
+	parseSpan := spans["parse request"]
+	assert.Equal(t, webhookSpan.SpanContext().SpanID(), parseSpan.Parent().SpanID())
+	assert.Contains(t, parseSpan.Attributes(), tracing.SenderIDKey.String("some-user"))
+	assert.Contains(t, parseSpan.Attributes(), tracing.InputChannelKey.String("slack"))
+	assert.Contains(t, parseSpan.Attributes(), tracing.EventCountKey.Int(2))
+	assert.Equal(t, parseSpan.SpanContext().SpanID(), spans["parse events"].Parent().SpanID())
+
+	assert.Contains(t, spans["lookup action"].Attributes(), tracing.ActionFoundKey.Bool(true))
+
+	runSpan := spans["run action"]
+	assert.Contains(t, runSpan.Attributes(), tracing.ActionNameKey.String("test-spans"))
+	assert.Contains(t, runSpan.Attributes(), tracing.InputChannelKey.String("slack"))
+	assert.Equal(t, runSpan.SpanContext().SpanID(), spans["custom span"].Parent().SpanID())
+}
+
+func TestTracingRecordsFailures(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	handler := New([]actions.Action{actions.Fallible(&FailingAction{err: errors.New("fail")})},
+		WithTracing(provider, nil)).Handler()
+
+	response := postWebhook(t, handler, `{"next_action": "test-failing"}`)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == "run action" || span.Name() == "POST /webhook" {
+			assert.Equal(t, codes.Error, span.Status().Code)
+		}
+	}
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	handler := New([]actions.Action{&TestAction{name: "test-action"}}, WithToken("secret")).Handler()
+	body := `{"next_action": "test-action"}`
+
+	tests := []struct {
+		url            string
+		authorization  string
+		expectedStatus int
+	}{
+		{"/webhook", "", http.StatusUnauthorized},
+		{"/webhook", "Bearer wrong", http.StatusUnauthorized},
+		{"/webhook?token=wrong", "", http.StatusUnauthorized},
+		{"/webhook", "Bearer secret", http.StatusOK},
+		{"/webhook?token=secret", "", http.StatusOK},
+	}
+
+	for _, testCase := range tests {
+		request, err := http.NewRequest("POST", testCase.url, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if testCase.authorization != "" {
+			request.Header.Set("Authorization", testCase.authorization)
+		}
+
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, testCase.expectedStatus, response.Code, testCase)
+	}
+}
+
+func TestHealthDoesNotRequireAuthentication(t *testing.T) {
+	request, err := http.NewRequest("GET", "/health", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	New(nil, WithToken("secret"), WithHMACSecret([]byte("secret"))).Handler().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestUnauthenticatedRequestIsNotParsed(t *testing.T) {
+	registry := metrics.NewRegistry()
+	handler := New(nil, WithToken("secret"), WithMetrics(registry)).Handler()
+
+	response := postWebhook(t, handler, `{"}`)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "Bearer", response.Header().Get("WWW-Authenticate"))
+
+	var rendered bytes.Buffer
+	if _, err := registry.WriteTo(&rendered); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(t, rendered.String(), "rasa_sdk_request_parse_failures_total 1")
+}
+
+func TestHMACAuthentication(t *testing.T) {
+	secret := []byte("hmac-secret")
+	handler := New([]actions.Action{&TestAction{name: "test-action"}}, WithHMACSecret(secret)).Handler()
+	body := []byte(`{"next_action": "test-action"}`)
+
+	tests := []struct {
+		signature      string
+		expectedStatus int
+	}{
+		{"", http.StatusUnauthorized},
+		{"sha256=not-hex", http.StatusUnauthorized},
+		{"sha256=" + hex.EncodeToString(Signature([]byte("wrong"), body)), http.StatusUnauthorized},
+		{hex.EncodeToString(Signature(secret, body)), http.StatusUnauthorized},
+		{"sha256=" + hex.EncodeToString(Signature(secret, body)), http.StatusOK},
+	}
+
+	for _, testCase := range tests {
+		request, err := http.NewRequest("POST", "/webhook", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request.Header.Set(SignatureHeader, testCase.signature)
+
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, testCase.expectedStatus, response.Code, testCase)
+	}
+}
+
+func TestAuthenticationFromEnvironment(t *testing.T) {
+	t.Setenv(TokenEnvironmentVariable, "env-token")
+	t.Setenv(HMACSecretEnvironmentVariable, "env-secret")
+
+	auth := New(nil).routerOptions.auth
+
+	assert.Equal(t, "env-token", auth.token)
+	assert.Equal(t, []byte("env-secret"), auth.hmacSecret)
+
+	assert.Equal(t, "option-token", New(nil, WithToken("option-token")).routerOptions.auth.token)
+
+	response := postWebhook(t, GetRouter(&TestAction{name: "test-action"}), `{"next_action": "test-action"}`)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestActionTimeout(t *testing.T) {
+	action := &BlockingAction{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(action.release)
+
+	handler := New([]actions.Action{action}, WithExecutionOptions(actions.WithTimeout(10*time.Millisecond),
+		actions.WithTimeoutFallback(&responses.Message{Text: "Sorry, this takes too long."}))).Handler()
+
+	response := postWebhook(t, handler, fmt.Sprintf(`{"next_action": "%s"}`, action.Name()))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"events":[],"responses":[{"text":"Sorry, this takes too long."}]}`, response.Body.String())
+}
+
+func TestPanicInActionWithTimeoutIsRecovered(t *testing.T) {
+	hook := test.NewGlobal()
+	handler := New([]actions.Action{&PanickingAction{}},
+		WithExecutionOptions(actions.WithTimeout(time.Second))).Handler()
+
+	response := postWebhook(t, handler, `{"next_action": "test-panic"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, hook.LastEntry().Data[logging.StackTraceKey], "PanickingAction")
+}
+
+type GatedAction struct {
+	name    string
+	running chan struct{}
+	release chan struct{}
+}
+
+func newGatedAction(name string) *GatedAction {
+	return &GatedAction{name: name, running: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (action *GatedAction) Run(_ *rasa.Tracker, _ *rasa.Domain, _ responses.ResponseDispatcher) []events.Event {
+	action.running <- struct{}{}
+	<-action.release
+
+	return []events.Event{}
+}
+func (action *GatedAction) Name() string { return action.name }
+
+func postWebhookAsync(t *testing.T, handler http.Handler, actionName string) chan *httptest.ResponseRecorder {
+	responses := make(chan *httptest.ResponseRecorder, 1)
+
+	go func() { responses <- postWebhook(t, handler, fmt.Sprintf(`{"next_action": "%s"}`, actionName)) }()
+
+	return responses
+}
+
+func TestGlobalConcurrencyLimitSheds(t *testing.T) {
+	first, second := newGatedAction("first"), newGatedAction("second")
+	handler := New([]actions.Action{first, second}, WithConcurrencyLimit(ConcurrencyLimit{MaxConcurrent: 1}),
+		WithRetryAfter(1500*time.Millisecond)).Handler()
+
+	firstResponse := postWebhookAsync(t, handler, "first")
+	<-first.running
+
+	response := postWebhook(t, handler, `{"next_action": "second"}`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, "2", response.Header().Get("Retry-After"))
+	assert.Contains(t, response.Body.String(), "action server is saturated")
+
+	close(first.release)
+	assert.Equal(t, http.StatusOK, (<-firstResponse).Code)
+}
+
+func TestQueuedRequestIsExecutedOnceSlotIsFree(t *testing.T) {
+	action := newGatedAction("gated")
+	registry := metrics.NewRegistry()
+	handler := New([]actions.Action{action}, WithMetrics(registry),
+		WithConcurrencyLimit(ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 1})).Handler()
+
+	firstResponse := postWebhookAsync(t, handler, "gated")
+	<-action.running
+
+	secondResponse := postWebhookAsync(t, handler, "gated")
+
+	assert.Eventually(t, func() bool {
+		var rendered bytes.Buffer
+		_, _ = registry.WriteTo(&rendered)
+
+		return strings.Contains(rendered.String(), `rasa_sdk_queued_requests{limiter="global"} 1`)
+	}, time.Second, 5*time.Millisecond)
+
+	response := postWebhook(t, handler, `{"next_action": "gated"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+
+	close(action.release)
+
+	assert.Equal(t, http.StatusOK, (<-firstResponse).Code)
+	assert.Equal(t, http.StatusOK, (<-secondResponse).Code)
+
+	var rendered bytes.Buffer
+	if _, err := registry.WriteTo(&rendered); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, rendered.String(), `rasa_sdk_queued_requests{limiter="global"} 0`)
+	assert.Contains(t, rendered.String(), `rasa_sdk_shed_requests_total{limiter="global"} 1`)
+}
+
+func TestQueueTimeout(t *testing.T) {
+	action := newGatedAction("gated")
+	handler := New([]actions.Action{action}, WithConcurrencyLimit(
+		ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 5, QueueTimeout: 20 * time.Millisecond})).Handler()
+
+	firstResponse := postWebhookAsync(t, handler, "gated")
+	<-action.running
+
+	response := postWebhook(t, handler, `{"next_action": "gated"}`)
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, "1", response.Header().Get("Retry-After"))
+
+	close(action.release)
+	assert.Equal(t, http.StatusOK, (<-firstResponse).Code)
+}
+
+func TestActionConcurrencyLimitOnlyAppliesToAction(t *testing.T) {
+	limited, other := newGatedAction("limited"), newGatedAction("other")
+	handler := New([]actions.Action{limited, other},
+		WithActionConcurrencyLimit("limited", ConcurrencyLimit{MaxConcurrent: 1})).Handler()
+
+	limitedResponse := postWebhookAsync(t, handler, "limited")
+	<-limited.running
+
+	assert.Equal(t, http.StatusServiceUnavailable, postWebhook(t, handler, `{"next_action": "limited"}`).Code)
+
+	otherResponse := postWebhookAsync(t, handler, "other")
+	<-other.running
+
+	close(limited.release)
+	close(other.release)
+
+	assert.Equal(t, http.StatusOK, (<-limitedResponse).Code)
+	assert.Equal(t, http.StatusOK, (<-otherResponse).Code)
+}
+
+func TestSaturatedActionDoesNotBlockGlobalSlots(t *testing.T) {
+	limited, idle := newGatedAction("limited"), newGatedAction("idle")
+	registry := metrics.NewRegistry()
+	handler := New([]actions.Action{limited, idle}, WithMetrics(registry),
+		WithConcurrencyLimit(ConcurrencyLimit{MaxConcurrent: 2, MaxQueued: 5}),
+		WithActionConcurrencyLimit("limited", ConcurrencyLimit{MaxConcurrent: 1, MaxQueued: 5})).Handler()
+
+	firstResponse := postWebhookAsync(t, handler, "limited")
+	<-limited.running
+
+	queuedResponses := []chan *httptest.ResponseRecorder{
+		postWebhookAsync(t, handler, "limited"), postWebhookAsync(t, handler, "limited")}
+
+	assert.Eventually(t, func() bool {
+		var rendered bytes.Buffer
+		_, _ = registry.WriteTo(&rendered)
+
+		return strings.Contains(rendered.String(), `rasa_sdk_queued_requests{limiter="limited"} 2`)
+	}, time.Second, 5*time.Millisecond)
+
+	idleResponse := postWebhookAsync(t, handler, "idle")
+
+	select {
+	case <-idle.running:
+	case <-time.After(time.Second):
+		t.Fatal("idle action wasn't served while another action was saturated")
+	}
+
+	close(idle.release)
+	close(limited.release)
+
+	assert.Equal(t, http.StatusOK, (<-idleResponse).Code)
+	assert.Equal(t, http.StatusOK, (<-firstResponse).Code)
+
+	for _, response := range queuedResponses {
+		assert.Equal(t, http.StatusOK, (<-response).Code)
+	}
+}
+
+func TestTimedOutActionKeepsSlotUntilFinished(t *testing.T) {
+	action := newGatedAction("gated")
+	handler := New([]actions.Action{action}, WithConcurrencyLimit(ConcurrencyLimit{MaxConcurrent: 1}),
+		WithExecutionOptions(actions.WithTimeout(10*time.Millisecond))).Handler()
+
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, `{"next_action": "gated"}`).Code)
+	<-action.running
+
+	assert.Equal(t, http.StatusServiceUnavailable, postWebhook(t, handler, `{"next_action": "gated"}`).Code)
+
+	action.release <- struct{}{}
+
+	assert.Eventually(t, func() bool {
+		response := postWebhook(t, handler, `{"next_action": "gated"}`)
+		if response.Code == http.StatusOK {
+			<-action.running
+			return true
+		}
+
+		return false
+	}, time.Second, 5*time.Millisecond)
+
+	close(action.release)
+}
+
+func TestServerWithDuplicateActionsFailsToStart(t *testing.T) {
+	actionServer := New([]actions.Action{&TestAction{name: "test-action"}, &TestAction{name: "test-action"}},
+		WithAddress("127.0.0.1:0"))
+
+	assert.IsType(t, &actions.DuplicateActionError{}, actionServer.Start())
+	assert.Nil(t, actionServer.Addr())
+}
+
+func TestGetRouterLogsDuplicateActions(t *testing.T) {
+	hook := test.NewGlobal()
+
+	router := GetRouter(&TestAction{name: "test-action"}, &TestAction{name: "test-action"})
+
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "test-action", hook.LastEntry().Data[logging.ActionNameKey])
+	assert.Equal(t, http.StatusOK, postWebhook(t, router, `{"next_action": "test-action"}`).Code)
+}
+
+func TestRegisterActionAtRuntime(t *testing.T) {
+	registry := &actions.Registry{}
+	actionServer := New([]actions.Action{&TestAction{name: "initial"}}, WithRegistry(registry))
+	handler := actionServer.Handler()
+
+	assert.Equal(t, registry, actionServer.Registry())
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, `{"next_action": "initial"}`).Code)
+	assert.Equal(t, http.StatusNotFound, postWebhook(t, handler, `{"next_action": "added"}`).Code)
+
+	assert.Nil(t, registry.Register(&TestAction{name: "added"}))
+	registry.Unregister("initial")
+
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, `{"next_action": "added"}`).Code)
+	assert.Equal(t, http.StatusNotFound, postWebhook(t, handler, `{"next_action": "initial"}`).Code)
+}
+
+type DescribedAction struct {
+	ContextAwareTestAction
+}
+
+func (action *DescribedAction) Name() string { return "described-action" }
+func (action *DescribedAction) Metadata() actions.Metadata {
+	return actions.Metadata{Description: "Does things.", RequiredSlots: []string{"city"}}
+}
+
+func TestListActions(t *testing.T) {
+	handler := GetRouter(&TestAction{name: "test-action"}, actions.Contextual(&DescribedAction{}),
+		&forms.FormValidationAction{FormName: "booking_form",
+			Validators: map[string]forms.SlotValidator{"date": nil, "guests": nil}})
+
+	request, err := http.NewRequest("GET", "/actions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	expectedResponse := `[{"name":"described-action","description":"Does things.","required_slots":["city"]},` +
+		`{"name":"test-action"},` +
+		`{"name":"validate_booking_form","required_slots":["date","guests"],"form_name":"booking_form"}]`
+	assert.JSONEq(t, expectedResponse, response.Body.String())
+}
+
+func TestListActionsRequiresAuthentication(t *testing.T) {
+	handler := New(nil, WithToken("secret")).Handler()
+
+	request, err := http.NewRequest("GET", "/actions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestDomainValidationAtStart(t *testing.T) {
+	actionServer := New([]actions.Action{&TestAction{name: "action_search_flights"}},
+		WithAddress("127.0.0.1:0"), WithDomainValidation("../rasa/testdata/domain.json"))
+
+	err := actionServer.Start()
+
+	assert.IsType(t, &actions.DomainMismatchError{}, err)
+	assert.Nil(t, actionServer.Addr())
+}
+
+func TestDomainValidationWithMissingFile(t *testing.T) {
+	actionServer := New(nil, WithAddress("127.0.0.1:0"), WithDomainValidation("missing.json"))
+
+	assert.NotNil(t, actionServer.Start())
+}
+
+func TestDomainValidationWithMatchingDomain(t *testing.T) {
+	actionServer := startTestServer(t, []actions.Action{&TestAction{name: "action_search_flights"},
+		&forms.FormValidationAction{FormName: "booking_form"}},
+		WithDomainValidation("../rasa/testdata/domain.json"))
+	defer actionServer.Shutdown(context.Background())
+
+	assert.NotNil(t, actionServer.Addr())
+}
+
+func TestRequestDomainValidation(t *testing.T) {
+	hook := test.NewGlobal()
+	handler := New([]actions.Action{&TestAction{name: "test-action"}}, WithRequestDomainValidation()).Handler()
+	body := `{"next_action": "test-action", "domain": {"actions": ["test-action", "other-action"]}}`
+
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, body).Code)
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, body).Code)
+
+	assert.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Contains(t, hook.LastEntry().Data[logging.ErrorKey].(error).Error(), "[other-action]")
+}
+
+func TestDomainValidationWithDomainDirectory(t *testing.T) {
+	actionServer := startTestServer(t, []actions.Action{&TestAction{name: "action_search_flights"},
+		&forms.FormValidationAction{FormName: "booking_form",
+			Validators: map[string]forms.SlotValidator{"city": nil, "guests": nil}}},
+		WithDomainValidation("../rasa/testdata/split_domain"))
+	defer actionServer.Shutdown(context.Background())
+
+	assert.NotNil(t, actionServer.Addr())
+}
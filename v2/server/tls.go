@@ -0,0 +1,197 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TLSCertFileEnvironmentVariable is the environment variable which can be used to specify the certificate file
+	// for serving HTTPS. See `WithTLSCertificate`.
+	TLSCertFileEnvironmentVariable = "RASA_SDK_TLS_CERT_FILE"
+	// TLSKeyFileEnvironmentVariable is the environment variable which can be used to specify the private key file
+	// for serving HTTPS. See `WithTLSCertificate`.
+	TLSKeyFileEnvironmentVariable = "RASA_SDK_TLS_KEY_FILE"
+	// TLSClientCAFileEnvironmentVariable is the environment variable which can be used to specify the certificate
+	// authorities which client certificates are verified with. See `WithClientCAFile`.
+	TLSClientCAFileEnvironmentVariable = "RASA_SDK_TLS_CLIENT_CA_FILE"
+)
+
+// ErrNoClientCertificates happens when the file passed to `WithClientCAFile` doesn't contain any certificates.
+var ErrNoClientCertificates = errors.New("no certificates found in client CA file")
+
+// ErrNoServerCertificate happens when `WithClientCAFile` is used without a server certificate, i.e. without
+// `WithTLSCertificate` or a `tls.Config` which provides certificates.
+var ErrNoServerCertificate = errors.New("client CA file requires a server certificate")
+
+// tlsFiles contains the files which are used to configure TLS.
+type tlsFiles struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+}
+
+// WithTLSCertificate makes the server serve HTTPS using the given PEM encoded certificate and private key files.
+// The files are reloaded automatically when they change so that rotated certificates are picked up without restart.
+// The files can also be set using the environment variables `RASA_SDK_TLS_CERT_FILE` and `RASA_SDK_TLS_KEY_FILE`.
+func WithTLSCertificate(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tlsFiles.certFile = certFile
+		s.tlsFiles.keyFile = keyFile
+	}
+}
+
+// WithClientCAFile enables mutual TLS. Clients have to present a certificate which was signed by one of the PEM
+// encoded certificate authorities in the given file. Use this together with `WithTLSCertificate` or
+// `WithTLSConfig`. The file can also be set using the environment variable `RASA_SDK_TLS_CLIENT_CA_FILE`.
+func WithClientCAFile(caFile string) Option {
+	return func(s *Server) { s.tlsFiles.clientCAFile = caFile }
+}
+
+func tlsFromEnvironment() []Option {
+	var options []Option
+
+	certFile, keyFile := os.Getenv(TLSCertFileEnvironmentVariable), os.Getenv(TLSKeyFileEnvironmentVariable)
+	if certFile != "" || keyFile != "" {
+		options = append(options, WithTLSCertificate(certFile, keyFile))
+	}
+
+	if caFile := os.Getenv(TLSClientCAFileEnvironmentVariable); caFile != "" {
+		options = append(options, WithClientCAFile(caFile))
+	}
+
+	return options
+}
+
+// tlsConfig returns the TLS configuration of the server which results from the given files and a potentially
+// configured `tls.Config`.
+func (files *tlsFiles) tlsConfig(existing *tls.Config, logger log.FieldLogger) (*tls.Config, error) {
+	if files.certFile == "" && files.keyFile == "" && files.clientCAFile == "" {
+		return existing, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if existing != nil {
+		config = existing.Clone()
+	}
+
+	if files.certFile != "" || files.keyFile != "" {
+		reloader, err := newCertificateReloader(files.certFile, files.keyFile, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		config.GetCertificate = reloader.certificate
+	}
+
+	if files.clientCAFile != "" {
+		pool, err := certPool(files.clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+
+		if !hasCertificate(config) {
+			return nil, ErrNoServerCertificate
+		}
+	}
+
+	return config, nil
+}
+
+func hasCertificate(config *tls.Config) bool {
+	return len(config.Certificates) > 0 || config.GetCertificate != nil || config.GetConfigForClient != nil
+}
+
+func certPool(caFile string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("%w: %s", ErrNoClientCertificates, caFile)
+	}
+
+	return pool, nil
+}
+
+// certificateReloader serves a certificate from files and reloads it in case the files were modified.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+	logger   log.FieldLogger
+
+	mutex       sync.Mutex
+	loaded      *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string, logger log.FieldLogger) (*certificateReloader, error) {
+	reloader := &certificateReloader{certFile: certFile, keyFile: keyFile, logger: logger}
+
+	if err := reloader.reloadIfModified(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// certificate returns the current certificate. It can be used as `GetCertificate` function of a `tls.Config`.
+func (reloader *certificateReloader) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if err := reloader.reloadIfModified(); err != nil {
+		// Keep serving the previous certificate, e.g. when only one of the files was rotated so far.
+		reloader.logger.Warnf("Reloading TLS certificate failed: %v", err)
+	}
+
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+
+	return reloader.loaded, nil
+}
+
+func (reloader *certificateReloader) reloadIfModified() error {
+	certInfo, err := os.Stat(reloader.certFile)
+	if err != nil {
+		return err
+	}
+
+	keyInfo, err := os.Stat(reloader.keyFile)
+	if err != nil {
+		return err
+	}
+
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+
+	if reloader.loaded != nil && certInfo.ModTime().Equal(reloader.certModTime) &&
+		keyInfo.ModTime().Equal(reloader.keyModTime) {
+		return nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return err
+	}
+
+	if reloader.loaded != nil {
+		reloader.logger.Info("Reloaded TLS certificate.")
+	}
+
+	reloader.loaded = &certificate
+	reloader.certModTime = certInfo.ModTime()
+	reloader.keyModTime = keyInfo.ModTime()
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+// newTestCertificate creates a certificate which is signed by `parent` or self-signed if `parent` is `nil`.
+func newTestCertificate(t *testing.T, commonName string, isCA bool, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCertificate) writeTo(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	if err := os.WriteFile(certFile, c.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, c.keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (c *testCertificate) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.certificate)
+
+	return pool
+}
+
+func tlsClient(rootCAs *x509.CertPool, clientCertificates ...tls.Certificate) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: rootCAs, Certificates: clientCertificates, MinVersion: tls.VersionTLS12},
+		// Don't reuse connections so that every request performs a new handshake.
+		DisableKeepAlives: true,
+	}}
+}
+
+func TestServeTLSWithCertificateReloading(t *testing.T) {
+	directory := t.TempDir()
+	certFile, keyFile := filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem")
+
+	ca := newTestCertificate(t, "ca", true, nil)
+	newTestCertificate(t, "first", false, ca).writeTo(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	actionServer := startTestServer(t, nil, WithTLSCertificate(certFile, keyFile))
+	defer actionServer.Shutdown(context.Background())
+
+	client := tlsClient(ca.pool())
+	url := fmt.Sprintf("https://%v/health", actionServer.Addr())
+
+	response, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "first", response.TLS.PeerCertificates[0].Subject.CommonName)
+
+	newTestCertificate(t, "rotated", false, ca).writeTo(t, certFile, keyFile, time.Now())
+
+	response, err = client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	assert.Equal(t, "rotated", response.TLS.PeerCertificates[0].Subject.CommonName)
+}
+
+func TestCertificateReloadingKeepsCertificateOnInvalidFiles(t *testing.T) {
+	directory := t.TempDir()
+	certFile, keyFile := filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem")
+
+	first := newTestCertificate(t, "first", false, nil)
+	first.writeTo(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	reloader, err := newCertificateReloader(certFile, keyFile, New(nil).logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the certificate was rotated so far so the key pair doesn't match.
+	second := newTestCertificate(t, "second", false, nil)
+	if err = os.WriteFile(certFile, second.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	served, err := reloader.certificate(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, first.certificate.Raw, served.Certificate[0])
+}
+
+func TestMutualTLS(t *testing.T) {
+	directory := t.TempDir()
+	certFile, keyFile := filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem")
+	caFile := filepath.Join(directory, "ca.pem")
+
+	ca := newTestCertificate(t, "ca", true, nil)
+	newTestCertificate(t, "server", false, ca).writeTo(t, certFile, keyFile, time.Now())
+
+	if err := os.WriteFile(caFile, ca.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	actionServer := startTestServer(t, nil, WithTLSCertificate(certFile, keyFile), WithClientCAFile(caFile))
+	defer actionServer.Shutdown(context.Background())
+
+	url := fmt.Sprintf("https://%v/health", actionServer.Addr())
+
+	_, err := tlsClient(ca.pool()).Get(url)
+	assert.NotNil(t, err)
+
+	untrusted := newTestCertificate(t, "untrusted", false, nil)
+	untrustedPair, err := tls.X509KeyPair(untrusted.certPEM, untrusted.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tlsClient(ca.pool(), untrustedPair).Get(url)
+	assert.NotNil(t, err)
+
+	client := newTestCertificate(t, "rasa", false, ca)
+	clientPair, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := tlsClient(ca.pool(), clientPair).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestTLSConfigurationErrorsAreReturnedOnStart(t *testing.T) {
+	directory := t.TempDir()
+
+	actionServer := New(nil, WithAddress("127.0.0.1:0"),
+		WithTLSCertificate(filepath.Join(directory, "missing.pem"), filepath.Join(directory, "missing-key.pem")))
+	assert.True(t, os.IsNotExist(actionServer.Start()))
+
+	emptyCAFile := filepath.Join(directory, "ca.pem")
+	if err := os.WriteFile(emptyCAFile, []byte("no certificates"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	actionServer = New(nil, WithAddress("127.0.0.1:0"), WithClientCAFile(emptyCAFile))
+	assert.ErrorIs(t, actionServer.Start(), ErrNoClientCertificates)
+}
+
+func TestClientCAFileRequiresServerCertificate(t *testing.T) {
+	directory := t.TempDir()
+	caFile := filepath.Join(directory, "ca.pem")
+
+	if err := os.WriteFile(caFile, newTestCertificate(t, "ca", true, nil).certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	actionServer := New(nil, WithAddress("127.0.0.1:0"), WithClientCAFile(caFile))
+	assert.ErrorIs(t, actionServer.Start(), ErrNoServerCertificate)
+	assert.Nil(t, actionServer.Addr())
+
+	server := newTestCertificate(t, "server", false, nil)
+	pair, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{pair}}
+	actionServer = New(nil, WithTLSConfig(config), WithClientCAFile(caFile))
+	assert.Nil(t, actionServer.configErr)
+}
+
+func TestTLSFromEnvironment(t *testing.T) {
+	directory := t.TempDir()
+	certFile, keyFile := filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem")
+	caFile := filepath.Join(directory, "ca.pem")
+
+	ca := newTestCertificate(t, "ca", true, nil)
+	ca.writeTo(t, certFile, keyFile, time.Now())
+
+	if err := os.WriteFile(caFile, ca.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(TLSCertFileEnvironmentVariable, certFile)
+	t.Setenv(TLSKeyFileEnvironmentVariable, keyFile)
+	t.Setenv(TLSClientCAFileEnvironmentVariable, caFile)
+
+	actionServer := New(nil)
+
+	assert.Nil(t, actionServer.configErr)
+	assert.NotNil(t, actionServer.httpServer.TLSConfig.GetCertificate)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, actionServer.httpServer.TLSConfig.ClientAuth)
+}
+
+func TestTLSCertificateExtendsTLSConfig(t *testing.T) {
+	directory := t.TempDir()
+	certFile, keyFile := filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem")
+	newTestCertificate(t, "server", false, nil).writeTo(t, certFile, keyFile, time.Now())
+
+	actionServer := New(nil, WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}),
+		WithTLSCertificate(certFile, keyFile))
+
+	assert.Equal(t, uint16(tls.VersionTLS13), actionServer.httpServer.TLSConfig.MinVersion)
+	assert.NotNil(t, actionServer.httpServer.TLSConfig.GetCertificate)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/wochinge/go-rasa-sdk/v2/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// webhookTracing contains the configuration to trace requests to the `/webhook` endpoint.
+type webhookTracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// statusRecorder remembers the status code which was sent to the client.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// traceRequests starts a span for every request. The trace context is extracted from the request headers so that
+// the span becomes part of the trace of the caller.
+func traceRequests(next http.HandlerFunc, webhook *webhookTracing) http.HandlerFunc {
+	if webhook == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := webhook.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := webhook.tracer.Start(ctx, r.Method+" "+r.URL.Path, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.method", r.Method), attribute.String("http.target", r.URL.Path)))
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.status_code", recorder.status))
+
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	}
+}
+
+func newWebhookTracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *webhookTracing {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	return &webhookTracing{tracer: provider.Tracer(tracing.InstrumentationName), propagator: propagator}
+}
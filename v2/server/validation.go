@@ -0,0 +1,51 @@
+package server
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/wochinge/go-rasa-sdk/v2/actions"
+	"github.com/wochinge/go-rasa-sdk/v2/logging"
+	"github.com/wochinge/go-rasa-sdk/v2/rasa"
+)
+
+// WithDomainValidation validates the served actions against the domain in the given file when the server is started
+// (see `actions.ValidateDomain`). `Start` returns an `*actions.DomainMismatchError` if the actions don't match the
+// domain. The domain can be a `domain.yml`, a directory of split domain files or a JSON file (see `rasa.LoadDomain`).
+func WithDomainValidation(domainFile string) Option {
+	return func(s *Server) { s.domainFile = domainFile }
+}
+
+// WithRequestDomainValidation validates the served actions against the domain of the first request from Rasa Open
+// Source. Differences are logged as warnings.
+func WithRequestDomainValidation() Option {
+	return func(s *Server) { s.routerOptions.domainValidation = &requestDomainValidation{} }
+}
+
+// validateDomainFile validates the actions of the registry against the domain in the given file or directory.
+func validateDomainFile(domainFile string, registry *actions.Registry) error {
+	domain, err := rasa.LoadDomain(domainFile)
+	if err != nil {
+		return err
+	}
+
+	return actions.ValidateDomain(registry.Actions(), domain)
+}
+
+type requestDomainValidation struct {
+	once sync.Once
+}
+
+// validate validates the actions of the registry against the domain of the first request.
+func (validation *requestDomainValidation) validate(domain *rasa.Domain, registry *actions.Registry,
+	logger log.FieldLogger) {
+	if validation == nil {
+		return
+	}
+
+	validation.once.Do(func() {
+		if err := actions.ValidateDomain(registry.Actions(), domain); err != nil {
+			logger.WithFields(log.Fields{logging.ErrorKey: err}).Warn("Served actions don't match the domain.")
+		}
+	})
+}